@@ -38,6 +38,9 @@ func (c *Carpy) Gen(base string) {
 func (c *Carpy) findCopyPkg() {
 	c.cpPkg = make(map[string]openapi.Package)
 	for _, pkg := range *c.pkgs {
+		if pkg.IsDependency() {
+			continue
+		}
 		for _, file := range pkg.GetAstPkg().Files {
 			if file.Scope == nil {
 				continue
@@ -81,10 +84,12 @@ func (c *Carpy) generateCopyFile() error {
 			if pkg.Path != st.to.Pkg.Path { //当前包不处理名称
 				imports[st.to.Pkg.Path] = st.to.Pkg.Name + md5.Encode16(st.to.Pkg.Path)
 			}
+			typeArgImports(pkg.Path, imports, st.to.TypeArgs)
 			for _, from := range st.from {
 				if pkg.Path != from.Pkg.Path { //当前包不处理名称
 					imports[from.Pkg.Path] = from.Pkg.Name + md5.Encode16(from.Pkg.Path)
 				}
+				typeArgImports(pkg.Path, imports, from.TypeArgs)
 			}
 		}
 		var buf bytes.Buffer
@@ -93,16 +98,10 @@ func (c *Carpy) generateCopyFile() error {
 		var funcBuf bytes.Buffer
 		for _, st := range *structs {
 			to := st.to
-			toName := to.Name
-			if pkg.Name != to.Pkg.Name || pkg.Path != to.Pkg.Path {
-				toName = imports[to.Pkg.Path] + "." + to.Name //包名.类型名
-			}
+			toName := structTypeName(pkg, imports, to)
 			var caseFromBuf bytes.Buffer
 			for funName, from := range st.from {
-				fromName := from.Name
-				if pkg.Name != from.Pkg.Name || pkg.Path != from.Pkg.Path {
-					fromName = imports[from.Pkg.Path] + "." + from.Name //包名.类型名
-				}
+				fromName := structTypeName(pkg, imports, from)
 				var funcBody bytes.Buffer
 				caseFromBuf.WriteString(fmt.Sprintf(templateFromCase, fromName, funName))
 				for _, tf := range to.Fields {
@@ -127,6 +126,49 @@ func (c *Carpy) generateCopyFile() error {
 	return nil
 }
 
+// typeArgImports 泛型类型参数所需的导入
+func typeArgImports(pkgPath string, imports map[string]string, args []openapi.Field) {
+	for _, arg := range args {
+		if arg.PkgPath != "" && arg.PkgPath != pkgPath {
+			imports[arg.PkgPath] = arg.Pkg + md5.Encode16(arg.PkgPath)
+		}
+		typeArgImports(pkgPath, imports, arg.TypeArgs)
+	}
+}
+
+// structTypeName 结构体类型名 包名.类型名 泛型实例化时还原为 包名.Page[包名.User]
+func structTypeName(pkg openapi.Package, imports map[string]string, st *openapi.Struct) string {
+	name := st.Name
+	if st.Origin != "" {
+		name = st.Origin + "[" + typeArgsName(pkg.Path, imports, st.TypeArgs) + "]"
+	}
+	if pkg.Name != st.Pkg.Name || pkg.Path != st.Pkg.Path {
+		name = imports[st.Pkg.Path] + "." + name
+	}
+	return name
+}
+
+func typeArgsName(pkgPath string, imports map[string]string, args []openapi.Field) string {
+	var names []string
+	for _, arg := range args {
+		name := arg.Type
+		if len(arg.TypeArgs) > 0 {
+			name += "[" + typeArgsName(pkgPath, imports, arg.TypeArgs) + "]"
+		}
+		if arg.PkgPath != "" && arg.PkgPath != pkgPath {
+			name = imports[arg.PkgPath] + "." + name
+		}
+		if arg.Ptr {
+			name = "*" + name
+		}
+		if arg.Array {
+			name = "[]" + name
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
 func writeField(funcBody *bytes.Buffer, imports *map[string]string, pkgName, structFieldName string, to openapi.Field, from openapi.Field, hasOpt bool) {
 	changeTyp := ""
 	baseFind := false
@@ -412,21 +454,34 @@ func (v *visitor) GetCallArgsInfo(args []ast.Expr) (to *openapi.Struct, from *op
 		} else {
 			info.Pkg = pkg.Name
 		}
+		v.resolveTypeArgs(info.TypeArgs)
+		st := v.carpy.pkgs.Instantiate(v.carpy.pkgs.FindStructPtr(path, info.Pkg, info.Type), info.TypeArgs)
+		if st == nil {
+			return
+		}
 		if i == 0 {
-			to = v.carpy.pkgs.FindStructPtr(path, info.Pkg, info.Type)
-			if to == nil {
-				return
-			}
+			to = st
 		} else if i == 1 {
-			from = v.carpy.pkgs.FindStructPtr(path, info.Pkg, info.Type)
-			if from == nil {
-				return
-			}
+			from = st
 		}
 	}
 	return
 }
 
+// resolveTypeArgs 补充泛型类型参数的包信息
+func (v *visitor) resolveTypeArgs(args []openapi.Field) {
+	pkg := v.carpy.cpPkg[v.name]
+	for i := range args {
+		if args[i].Pkg != "" {
+			args[i].PkgPath = v.imports[args[i].Pkg]
+		} else {
+			args[i].Pkg = pkg.Name
+			args[i].PkgPath = pkg.Path
+		}
+		v.resolveTypeArgs(args[i].TypeArgs)
+	}
+}
+
 func getExprInfo(expr ast.Expr) *openapi.Field {
 	switch exp := expr.(type) {
 	case *ast.Ident:
@@ -451,6 +506,9 @@ func getExprInfo(expr ast.Expr) *openapi.Field {
 		return getExprInfo(exp.X)
 	case *ast.CompositeLit:
 		return GetCompositeLitInfo(exp)
+	case *ast.IndexExpr, *ast.IndexListExpr: //泛型 Page[User]
+		f := openapi.GetExprInfo(exp)
+		return &f
 	}
 	return nil
 }
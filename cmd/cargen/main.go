@@ -2,8 +2,11 @@ package main
 
 import (
 	"flag"
+	"os"
+	"strings"
 
 	"github.com/carlos-yuan/cargen/cmd/gen"
+	"github.com/carlos-yuan/cargen/mock"
 )
 
 func main() {
@@ -21,7 +24,25 @@ func main() {
 	flag.StringVar(&conf.DictType, "dictType", "type", "字典名称字段名")
 	flag.StringVar(&conf.DictName, "dictName", "name", "字典标签字段名")
 	flag.StringVar(&conf.DictValue, "dictValue", "value", "字典值字段名")
-	flag.Parse()
+	flag.StringVar(&conf.Format, "format", gen.DocFormatOpenApi, "文档格式 openapi postman md html")
+	flag.BoolVar(&conf.Proto, "proto", false, "通过biz/<name>/rpc下的proto生成rpc接口文档")
+	flag.StringVar(&conf.Web, "web", gen.WebGin, "路由生成目标框架 gin hertz(需-tags hertz编译) std")
+	flag.BoolVar(&conf.Merge, "merge", false, "proto文档与gin接口合并为一个文档")
+	flag.StringVar(&conf.Mock.Addr, "addr", mock.DefaultAddr, "模拟服务监听地址")
+	flag.DurationVar(&conf.Mock.Latency, "latency", 0, "模拟服务响应延迟 如200ms")
+	flag.Float64Var(&conf.Mock.ErrorRate, "error", 0, "模拟服务错误注入概率 0-1")
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") { //子命令 cargen doc --format postman
+		conf.Gen = os.Args[1]
+		flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
+	if conf.Gen == gen.GenDoc && flag.Arg(0) == gen.DocCmdDiff { //cargen doc diff old.json new.json
+		if !gen.DocDiff(os.Stdout, flag.Arg(1), flag.Arg(2)) {
+			os.Exit(1)
+		}
+		return
+	}
 	if conf.Path == "" {
 		panic("项目目录不能为空")
 	}
@@ -3,6 +3,8 @@ package gen
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -11,11 +13,22 @@ import (
 	"github.com/carlos-yuan/cargen/util/fileUtil"
 )
 
-// CreateApiRouter 生成api路由
+// CreateApiRouter 生成gin api路由
 func CreateApiRouter(genPath string) {
+	CreateWebRouter(genPath, WebGin)
+}
+
+// CreateWebRouter 生成指定web框架的api路由 gin hertz std
+func CreateWebRouter(genPath, web string) {
+	rw, ok := routerWebs[web]
+	if !ok {
+		panic("unsupported web framework: " + web)
+	}
 	pkgs := openapi.Packages{}
-	pkgs.Init(genPath)
+	pkgs.Init(genPath, rw.Tags...)
 	var routers = make(map[string]string) //map[文件路径]代码
+	var routes []RouteEntry               //完整路由表 写入前校验
+	var manifest = make(PermissionManifests)
 	for _, pkg := range pkgs {
 		for _, s := range pkg.Structs {
 			sort.Slice(s.Api, func(i, j int) bool {
@@ -30,60 +43,122 @@ func CreateApiRouter(genPath string) {
 					importInfo += ` "` + pkg.Path + `"`
 				}
 				var apiWriter bytes.Buffer
+				useMws, usePerm := false, false
 				for _, api := range s.Api {
+					handles := ""
+					for _, mw := range api.Middlewares { //具名中间件 在接口处理前执行
+						handles += "mws.Get(`" + mw + "`), "
+						useMws = true
+					}
+					recv, call := "t", "t."+api.Name+"()"
+					if api.WebSocket { //websocket 鉴权后升级连接
+						recv, call = "c", "ctl."+rw.Prefix+"WebSocketBind("+rw.Writer+", c, t."+api.Name+")"
+						if api.NoParams {
+							call = "ctl." + rw.Prefix + "WebSocket(" + rw.Writer + ", c, t." + api.Name + ")"
+						}
+					} else if api.Typed { //类型化接口 由生成代码绑定参数并转换返回
+						recv, call = "c", "ctl.Handle(c, t."+api.Name+")"
+						if api.NoParams {
+							call = "ctl.HandleNoParams(c, t." + api.Name + ")"
+						}
+					}
 					checkToken := ""
 					if api.Auth != "" { //鉴权加载
 						auth := api.Auth
 						if api.AuthTo != "" && api.AuthTo != api.Auth {
 							auth += ":" + api.AuthTo
 						}
-						checkToken = "\n\t\t\t\tt.CheckToken(tokenMap[`" + auth + "`])"
+						checkToken = "\n\t\t\t\t" + recv + ".CheckToken(tokenMap[`" + auth + "`])"
 					}
-					urlPath := api.GetRequestPathNoGroup()
-					if api.Params != nil {
-						for _, param := range api.Params.Fields {
-							if param.In == openapi.OpenApiInPath {
-								urlPath = strings.ReplaceAll(urlPath, "{"+param.ParamName+"}", ":"+param.ParamName)
-							}
-						}
+					if len(api.Permissions) > 0 { //权限校验 在鉴权之后
+						checkToken += "\n\t\t\t\tctl.CheckPermission(perm, " + recv + ", `" + strings.Join(api.Permissions, "`, `") + "`)"
+						usePerm = true
 					}
-					//t.Success([]byte)纯二进制返回判断
-					isByteArray := false
-					for _, f := range api.Response.Fields {
-						if f.Name == "Data" && f.Type == "byte" && f.Array {
-							isByteArray = true
-						}
+					manifest.Add(pkg.ModPath, api, s.Name)
+					meta := routeMeta(genPath, s.Name, api)
+					deprecated := ""
+					if api.Deprecated { //已废弃接口返回Deprecation头
+						deprecated = "\n\t\t\t\t" + fmt.Sprintf(rw.Header, `"Deprecation"`, `"true"`)
+					}
+					summary := ""
+					if api.Summary != "" {
+						summary = "\n\t\t\t// " + api.Summary
 					}
-					if !isByteArray {
-						apiWriter.WriteString(fmt.Sprintf("\n\t\t\tctl.GinRegister{Method: \"%s\", Path: prefix + `%s`, Handles: []gin.HandlerFunc{func(ctx *gin.Context) {"+
-							"\n\t\t\t\tt := t.SetContext(ctx)"+
-							"%s"+ //鉴权
-							"\n\t\t\t\tctx.JSON(200, t.%s())"+
-							"\n\t\t\t}}},",
-							strings.ToUpper(api.HttpMethod),
-							urlPath,
-							checkToken,
-							api.Name,
-						))
+					urlPath := rw.Path(api.GetRequestPathNoGroup(), api)
+					routes = append(routes, RouteEntry{Method: strings.ToUpper(api.HttpMethod), Path: rw.Path(api.GetRequestPath(), api), File: api.Path, Func: s.Name + "." + api.Name})
+					urlPath = "group + `" + urlPath + "`"
+					routeGroup := "prefix"
+					if version := api.GetVersionPrefix(); version != "" { //版本分组 @GET|v2或api/user/v2
+						routeGroup += " + `" + version + "`"
+					}
+					render := "JSON"
+					if api.ResponseType == openapi.ResponseTypeXML {
+						render = "XML"
+					}
+					var body string
+					if api.IsStream() { //SSE推送、流式下载或websocket
+						if !rw.Stream {
+							panic(fmt.Sprintf("%s %s.%s: sse, stream and ws are not supported on %s", api.Path, s.Name, api.Name, web))
+						}
+						body = "\n\t\t\t\tctl." + rw.Prefix + "SSE(" + rw.Writer + ", " + call + ")"
+						if api.WebSocket {
+							body = "\n\t\t\t\t" + call
+						} else if api.ResponseType == openapi.ResponseTypeStream { //stream:text/csv 由Stream成功输出时设置
+							body = "\n\t\t\t\tctl." + rw.Prefix + "Stream(" + rw.Writer + ", " + call + ", `" + api.ContentType + "`)"
+						}
+					} else if api.IsBytes() { //bytes:image/png或t.Success([]byte) 成功时才设置Content-Type
+						if !api.IsByteArrayData() && !api.IsDataAny() {
+							panic(fmt.Sprintf("%s %s.%s: bytes response requires []byte data", api.Path, s.Name, api.Name))
+						}
+						body = "\n\t\t\t\tres := " + call + "\n\t\t\t\t" + fmt.Sprintf(rw.Bytes, "`"+api.ContentType+"`")
 					} else {
-						apiWriter.WriteString(fmt.Sprintf("\n\t\t\tctl.GinRegister{Method: \"%s\", Path: prefix + `%s`, Handles: []gin.HandlerFunc{func(ctx *gin.Context) {"+
-							"\n\t\t\t\tt := t.SetContext(ctx)"+
-							"%s"+ //鉴权
-							"\n\t\t\t\tres := t.%s()"+ //鉴权
-							"\n\t\t\t\tctx.Writer.WriteHeader(res.Code)"+
-							"\n\t\t\t\tctx.Writer.Write(res.Data.([]byte))"+
-							"\n\t\t\t}}},",
-							strings.ToUpper(api.HttpMethod),
-							urlPath,
-							checkToken,
-							api.Name,
-						))
+						body = "\n\t\t\t\t" + fmt.Sprintf(rw.Render, render, call)
 					}
+					apiWriter.WriteString(fmt.Sprintf("%s\n\t\t\tctl.%sRegister{Method: \"%s\", Group: %s, Path: %s, Meta: %s, Handles: []%s{%s%s {"+
+						"\n\t\t\t\t%s := t.SetContext(%s)"+
+						"%s"+ //鉴权
+						"%s"+ //废弃
+						"%s"+
+						"\n\t\t\t%s}},",
+						summary,
+						rw.Prefix,
+						strings.ToUpper(api.HttpMethod),
+						routeGroup,
+						urlPath,
+						meta,
+						rw.Handler,
+						handles,
+						rw.Func,
+						recv,
+						rw.Request,
+						checkToken,
+						deprecated,
+						body,
+						rw.FuncEnd,
+					))
 				}
-				routers[path] = fmt.Sprintf(apiRouterTemplate, "\t"+importInfo, pkg.Name+"."+s.Name, apiWriter.String())
+				invokeArgs := ""
+				if useMws {
+					invokeArgs += ", mws ctl." + rw.Prefix + "Middlewares"
+				}
+				if usePerm {
+					invokeArgs += ", perm ctl.PermissionChecker"
+				}
+				routers[path] = fmt.Sprintf(apiRouterTemplate, rw.Std, rw.Import+"\t"+importInfo, pkg.Name+"."+s.Name, invokeArgs, rw.Prefix, apiWriter.String())
 			}
 		}
 	}
+	if err := rw.Check(routes); err != nil {
+		panic(err)
+	}
+	for path, src := range manifest.Files() { //权限清单 供管理后台导入
+		routers[path] = src
+	}
+	for _, path := range manifest.Stale() { //权限全部移除后删除旧清单
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			panic(err)
+		}
+	}
 	for path, src := range routers {
 		err := fileUtil.WriteByteFile(path, []byte(src))
 		if err != nil {
@@ -92,24 +167,131 @@ func CreateApiRouter(genPath string) {
 	}
 }
 
+const (
+	WebGin   = "gin"
+	WebHertz = "hertz"
+	WebStd   = "std"
+)
+
+// RouterWeb 路由生成目标框架的代码片段
+type RouterWeb struct {
+	Prefix  string                                    //ctl中的类型前缀 GinRegister GinMiddlewares NewGinContext
+	Std     string                                    //标准库导入
+	Import  string                                    //框架包导入
+	Handler string                                    //处理函数类型
+	Func    string                                    //处理函数签名
+	FuncEnd string                                    //处理函数结尾
+	Request string                                    //SetContext参数
+	Header  string                                    //设置响应头 %s为键和值
+	Render  string                                    //输出返回体 %s为JSON/XML及返回体
+	Bytes   string                                    //输出二进制返回体res %s为Content-Type
+	Writer  string                                    //sse、stream及ws输出参数
+	Stream  bool                                      //支持sse、stream及ws
+	Path    func(path string, api openapi.Api) string //路径参数转换
+	Tags    []string                                  //加载项目代码时的构建标签
+	Check   func(routes []RouteEntry) error           //按框架的路由规则校验路由表
+}
+
+var routerWebs = map[string]RouterWeb{
+	WebGin: {
+		Prefix:  "Gin",
+		Import:  "\t\"github.com/gin-gonic/gin\"\n",
+		Handler: "gin.HandlerFunc",
+		Func:    "func(ctx *gin.Context)",
+		FuncEnd: "}",
+		Request: "ctx",
+		Header:  "ctx.Header(%s, %s)",
+		Render:  "ctx.%s(200, %s)",
+		Bytes:   "ctl.GinBytes(ctx, res, %s)",
+		Writer:  "ctx",
+		Stream:  true,
+		Path:    ginParamPath,
+		Check:   CheckRoutes,
+	},
+	WebHertz: { //需使用-tags hertz编译
+		Prefix:  "Hertz",
+		Std:     "\t\"context\"\n\n",
+		Import:  "\t\"github.com/cloudwego/hertz/pkg/app\"\n",
+		Handler: "app.HandlerFunc",
+		Func:    "func(ctx context.Context, rc *app.RequestContext)",
+		FuncEnd: "}",
+		Request: "ctl.NewHertzRequest(ctx, rc)",
+		Header:  "rc.Header(%s, %s)",
+		Render:  "rc.%s(200, %s)",
+		Bytes:   "ctl.HertzBytes(rc, res, %s)",
+		Path:    ginParamPath,
+		Tags:    []string{"hertz"},
+		Check:   CheckRoutes,
+	},
+	WebStd: { //Go1.22 ServeMux 路径参数保持{id}
+		Prefix:  "Std",
+		Std:     "\t\"net/http\"\n\n",
+		Handler: "ctl.StdHandler",
+		Func:    "ctl.StdHandle(func(w http.ResponseWriter, r *http.Request)",
+		FuncEnd: "})",
+		Request: "ctl.NewStdRequest(w, r)",
+		Header:  "w.Header().Set(%s, %s)",
+		Render:  "ctl.Std%s(w, %s)",
+		Bytes:   "ctl.StdBytes(w, res, %s)",
+		Writer:  "w, r",
+		Stream:  true,
+		Path:    func(path string, _ openapi.Api) string { return path },
+		Check:   CheckStdRoutes,
+	},
+}
+
+// routeMeta 路由元信息 用于/debug/routes
+func routeMeta(genPath, group string, api openapi.Api) string {
+	file := api.Path
+	if rel, err := filepath.Rel(genPath, api.Path); err == nil {
+		file = filepath.ToSlash(rel)
+	}
+	meta := fmt.Sprintf("ctl.RouteMeta{Controller: %q, Func: %q", group, api.Name)
+	if api.Auth != "" {
+		auth := api.Auth
+		if api.AuthTo != "" && api.AuthTo != api.Auth {
+			auth += ":" + api.AuthTo
+		}
+		meta += fmt.Sprintf(", Auth: %q", auth)
+	}
+	if len(api.Permissions) > 0 {
+		meta += fmt.Sprintf(", Permissions: %#v", api.Permissions)
+	}
+	if api.Summary != "" {
+		meta += fmt.Sprintf(", Summary: %q", api.Summary)
+	}
+	return meta + fmt.Sprintf(", File: %q}", file)
+}
+
+// ginParamPath 路径参数{id}转换为gin的:id
+func ginParamPath(path string, api openapi.Api) string {
+	if api.Params != nil {
+		for _, param := range api.Params.Fields {
+			if param.In == openapi.OpenApiInPath {
+				path = strings.ReplaceAll(path, "{"+param.ParamName+"}", ":"+param.ParamName)
+			}
+		}
+	}
+	return path
+}
+
 const apiRouterTemplate = `// Code generated by car-gen. DO NOT EDIT.
 // Code generated by car-gen. DO NOT EDIT.
 // Code generated by car-gen. DO NOT EDIT.
 package router
 
 import (
-	"github.com/carlos-yuan/cargen/core/config"
+%s	"github.com/carlos-yuan/cargen/core/config"
 	ctl "github.com/carlos-yuan/cargen/core/controller"
 	"github.com/carlos-yuan/cargen/util/convert"
-	"github.com/gin-gonic/gin"
 %s
 )
 
 func init() {
-	err := config.Container.Invoke(func(t *%s, c *config.Config) {
+	err := config.Container.Invoke(func(t *%s, c *config.Config%s) {
 		mod, name := convert.GetStructModAndName(t)
-		t.ControllerContext = ctl.NewGinContext(c.Web[mod])
-		prefix := c.Web[mod].Prefix + mod + "/" + convert.FistToLower(name)
+		t.ControllerContext = ctl.New%sContext(c.Web[mod])
+		prefix, group := c.Web[mod].Prefix+mod+"/", convert.FistToLower(name)
 		routerList = append(routerList,
 %s
 		)
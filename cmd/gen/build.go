@@ -2,10 +2,12 @@ package gen
 
 import (
 	"github.com/carlos-yuan/cargen/util/doc"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/carlos-yuan/cargen/enum"
+	"github.com/carlos-yuan/cargen/mock"
 	openapi "github.com/carlos-yuan/cargen/open_api"
 	"github.com/carlos-yuan/cargen/util/fileUtil"
 )
@@ -25,6 +27,11 @@ type Config struct {
 	DictLabel string //字典标签字段名
 	DictValue string //字典值字段名
 	Out       string //输出目录
+	Format    string //文档格式 openapi postman md html
+	Proto     bool   //通过proto生成rpc接口文档
+	Merge     bool   //proto文档与gin接口合并
+	Web       string //路由生成目标框架 gin hertz std
+	Mock      mock.Config
 }
 
 const (
@@ -34,6 +41,15 @@ const (
 	GenRouter = "router"
 	GenEnum   = "enum"
 	GenConfig = "config"
+	GenMock   = "mock"
+	GenCrud   = "crud"
+)
+
+const (
+	DocFormatOpenApi = "openapi"
+	DocFormatPostman = "postman"
+	DocFormatMd      = "md"
+	DocFormatHtml    = "html"
 )
 
 func (c Config) Build() {
@@ -53,18 +69,36 @@ func (c Config) Build() {
 			CarGen(c.Name, c.DbName, projectPath+"/rpc/kitex_gen/pb"+c.Name, "pb"+c.Name, projectPath+"/service/", "service")
 		}
 	case GenRouter:
-		CreateApiRouter(c.Path)
+		if c.Web == "" {
+			c.Web = WebGin
+		}
+		CreateWebRouter(c.Path, c.Web)
 	case GenDB:
 		GormGen(c.Path, c.DbDsn, c.DbName, strings.Split(c.Tables, ","))
 		if c.DictTable != "" { //检测是否传入字典表
 			enum.GenEnum(c.Path+"/orm/"+c.DbName, c.DictTable, c.DictType, c.DictName, c.DictLabel, c.DictValue, c.DbDsn)
 		}
 	case GenDoc:
-		openapi.GenFromPath(c.Name, c.Des, c.Version, c.Path, c.Out)
+		if c.Proto { //cargen doc --proto
+			openapi.GenFromProto(c.Name, c.Des, c.Version, c.Path, c.Out, c.Merge)
+			break
+		}
+		switch c.Format {
+		case DocFormatPostman:
+			openapi.GenPostmanFromPath(c.Name, c.Des, c.Version, c.Path, c.Out)
+		case DocFormatMd, DocFormatHtml:
+			openapi.GenReferenceFromPath(c.Name, c.Des, c.Version, c.Path, c.Out, c.Format)
+		default:
+			openapi.GenFromPath(c.Name, c.Des, c.Version, c.Path, c.Out)
+		}
 	case GenEnum:
 		enum.GenEnum(c.Path, c.DictTable, c.DictType, c.DictName, c.DictLabel, c.DictValue, c.DbDsn)
 	case GenConfig:
 		ConfigGen(c.Path, c.Name, c.Out)
+	case GenCrud: //cargen crud -t user,goods
+		CrudGen(c.Path, c.DbName, c.Name, strings.Split(c.Tables, ","))
+	case GenMock:
+		log.Fatal(mock.Run(c.Path, c.Mock))
 	}
 	if c.Path != "" {
 		doc.GoFmt(c.Path)
@@ -0,0 +1,500 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/carlos-yuan/cargen/util/convert"
+	"github.com/carlos-yuan/cargen/util/fileUtil"
+	"golang.org/x/mod/modfile"
+)
+
+// CrudGen 通过gorm生成的model及query生成增删改查接口
+// 控制器及请求返回体写入api/<name> 服务写入service 已存在的文件不覆盖
+func CrudGen(path, dbName, name string, tables []string) {
+	b, err := os.ReadFile(path + "/go.mod")
+	if err != nil {
+		panic(err)
+	}
+	modPath := modfile.ModulePath(b)
+	if modPath == "" {
+		panic("go.mod未声明module")
+	}
+	if name == "" {
+		name = dbName
+	}
+	models, err := parseCrudModels(path+"/orm/"+dbName+"/model", tables)
+	if err != nil {
+		panic(err)
+	}
+	queryFields, err := parseCrudQuery(path+"/orm/"+dbName+"/query", models)
+	if err != nil {
+		panic(err)
+	}
+	files := make(map[string][]byte)
+	for _, m := range models {
+		m.filters(queryFields[m.Name])
+		modelImport := `"` + modPath + "/orm/" + dbName + `/model"`
+		files[path+"/api/"+name+"/"+convert.ToSnakeCase(m.Table)+".go"] = m.controller(name, modelImport, `"`+modPath+`/service"`)
+		files[path+"/service/"+convert.ToSnakeCase(m.Table)+".go"] = m.service(modelImport, `"`+modPath+"/orm/"+dbName+`/query"`)
+	}
+	for file, src := range files {
+		if fileUtil.IsExist(file) { //生成后由业务修改 不覆盖
+			log.Printf("crud skip existing file: %s", file)
+			continue
+		}
+		code, err := format.Source(src)
+		if err != nil {
+			panic(fmt.Sprintf("%s: %v\n%s", file, err, src))
+		}
+		if err = fileUtil.WriteByteFile(file, code); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// crudModel gorm生成的模型
+type crudModel struct {
+	Name    string
+	Table   string
+	Fields  []crudField
+	Key     *crudField        //主键 用于详情、更新和删除
+	Imports map[string]string //模型文件的导入 map[包名]导入
+	Filter  []crudField       //列表过滤条件 可空字段
+}
+
+type crudField struct {
+	Name       string
+	Type       string //Go类型 可空字段为指针
+	Json       string
+	Comment    string
+	Column     string
+	NotNull    bool
+	HasDefault bool
+	PrimaryKey bool
+	AutoInc    bool
+	Size       int    //字符串长度 来自type:varchar(32)或size:32
+	QueryType  string //query中的字段类型 field.String
+}
+
+var crudSizeReg = regexp.MustCompile(`^(?:var)?char\((\d+)\)`)
+
+// parseCrudModels 按表名查找TableName常量对应的模型 tables为空时为全部模型
+func parseCrudModels(modelPath string, tables []string) ([]*crudModel, error) {
+	pkg, err := parseDir(modelPath, "model")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", modelPath, err)
+	}
+	tableNames := make(map[string]string) //map[模型名]表名
+	var structs = make(map[string]*ast.StructType)
+	var imports = make(map[string]map[string]string)
+	for _, file := range pkg.Files {
+		fileImports := make(map[string]string)
+		for _, imp := range file.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			if imp.Name != nil {
+				fileImports[imp.Name.Name] = imp.Name.Name + " " + imp.Path.Value
+			} else {
+				fileImports[convert.LastName(p)] = imp.Path.Value
+			}
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				switch s := spec.(type) {
+				case *ast.ValueSpec:
+					for i, n := range s.Names {
+						if !strings.HasPrefix(n.Name, "TableName") || i >= len(s.Values) {
+							continue
+						}
+						if lit, ok := s.Values[i].(*ast.BasicLit); ok {
+							tableNames[strings.TrimPrefix(n.Name, "TableName")], _ = strconv.Unquote(lit.Value)
+						}
+					}
+				case *ast.TypeSpec:
+					if st, ok := s.Type.(*ast.StructType); ok {
+						structs[s.Name.Name] = st
+						imports[s.Name.Name] = fileImports
+					}
+				}
+			}
+		}
+	}
+	var models []*crudModel
+	if len(tables) == 0 || len(tables) == 1 && tables[0] == "" {
+		for name := range tableNames {
+			tables = append(tables, tableNames[name])
+		}
+	}
+	for _, table := range tables {
+		table = strings.TrimSpace(table)
+		name := ""
+		for n, t := range tableNames {
+			if t == table {
+				name = n
+				break
+			}
+		}
+		st := structs[name]
+		if name == "" || st == nil {
+			return nil, fmt.Errorf("%s: model of table %s not found", modelPath, table)
+		}
+		m := &crudModel{Name: name, Table: table, Imports: imports[name]}
+		for _, fd := range st.Fields.List {
+			if len(fd.Names) != 1 || fd.Tag == nil {
+				continue
+			}
+			f := crudField{Name: fd.Names[0].Name, Type: types.ExprString(fd.Type)}
+			tag, _ := strconv.Unquote(fd.Tag.Value)
+			f.parseGormTag(tag)
+			m.Fields = append(m.Fields, f)
+		}
+		for i := range m.Fields {
+			if m.Fields[i].PrimaryKey {
+				m.Key = &m.Fields[i]
+				break
+			}
+		}
+		if m.Key == nil {
+			return nil, fmt.Errorf("%s: table %s has no primary key", modelPath, table)
+		}
+		models = append(models, m)
+	}
+	return models, nil
+}
+
+func (f *crudField) parseGormTag(tag string) {
+	f.Json = f.Name
+	if name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ","); name != "" {
+		f.Json = name
+	}
+	for _, s := range strings.Split(reflect.StructTag(tag).Get("gorm"), ";") {
+		k, v, _ := strings.Cut(s, ":")
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "column":
+			f.Column = v
+		case "primarykey":
+			f.PrimaryKey = true
+		case "autoincrement":
+			f.AutoInc = v != "false"
+		case "not null":
+			f.NotNull = true
+		case "default":
+			f.HasDefault = true
+		case "comment":
+			f.Comment = v
+		case "type":
+			if size := crudSizeReg.FindStringSubmatch(strings.ToLower(v)); size != nil {
+				f.Size, _ = strconv.Atoi(size[1])
+			}
+		case "size":
+			f.Size, _ = strconv.Atoi(v)
+		case "autocreatetime", "autoupdatetime":
+			f.HasDefault = true
+		}
+	}
+	if f.Comment == "" {
+		f.Comment = f.Column
+	}
+}
+
+// parseCrudQuery 读取query中模型对应的字段类型 并检查<Model>Dao是否已生成
+func parseCrudQuery(queryPath string, models []*crudModel) (map[string]map[string]string, error) {
+	pkg, err := parseDir(queryPath, "query")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", queryPath, err)
+	}
+	structs := make(map[string]*ast.StructType)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok {
+				for _, spec := range gd.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						if st, ok := ts.Type.(*ast.StructType); ok {
+							structs[ts.Name.Name] = st
+						}
+					}
+				}
+			}
+		}
+	}
+	query := structs["Query"]
+	if query == nil {
+		return nil, fmt.Errorf("%s: Query not found", queryPath)
+	}
+	fields := make(map[string]map[string]string) //map[模型名]map[字段名]字段类型
+	for _, m := range models {
+		if structs[m.Name+"Dao"] == nil {
+			return nil, fmt.Errorf("%s: %sDao not found, run cargen %s first", queryPath, m.Name, GenDB)
+		}
+		for _, fd := range query.Fields.List {
+			if len(fd.Names) != 1 || fd.Names[0].Name != m.Name {
+				continue
+			}
+			ident, ok := fd.Type.(*ast.Ident)
+			if !ok || structs[ident.Name] == nil {
+				break
+			}
+			fields[m.Name] = make(map[string]string)
+			for _, qf := range structs[ident.Name].Fields.List {
+				if len(qf.Names) == 1 {
+					fields[m.Name][qf.Names[0].Name] = types.ExprString(qf.Type)
+				}
+			}
+		}
+		if fields[m.Name] == nil {
+			return nil, fmt.Errorf("%s: query of %s not found", queryPath, m.Name)
+		}
+	}
+	return fields, nil
+}
+
+// crudFilterTypes 可作为列表过滤条件的query字段类型
+var crudFilterTypes = map[string]bool{
+	"field.String": true, "field.Bool": true, "field.Time": true, "field.Float32": true, "field.Float64": true,
+	"field.Int": true, "field.Int8": true, "field.Int16": true, "field.Int32": true, "field.Int64": true,
+	"field.Uint": true, "field.Uint8": true, "field.Uint16": true, "field.Uint32": true, "field.Uint64": true,
+}
+
+// filters 可空字段作为列表过滤条件 时间维护字段除外
+func (m *crudModel) filters(queryFields map[string]string) {
+	for i := range m.Fields {
+		f := &m.Fields[i]
+		f.QueryType = queryFields[f.Name]
+		if strings.HasPrefix(f.Type, "*") && !f.auto() && crudFilterTypes[f.QueryType] {
+			m.Filter = append(m.Filter, *f)
+		}
+	}
+}
+
+// auto 由数据库或gorm维护的字段 不在新增和更新参数中
+func (f crudField) auto() bool {
+	switch f.Name {
+	case "CreatedAt", "UpdatedAt", "DeletedAt":
+		return true
+	}
+	return f.Type == "gorm.DeletedAt" || f.PrimaryKey && f.AutoInc
+}
+
+// validate 按字段约束生成校验规则 非空且无默认值的字符串必填 有长度时限制最大长度
+func (f crudField) validate() string {
+	var rules []string
+	if f.Type == "string" && f.NotNull && !f.HasDefault {
+		rules = append(rules, "required")
+	}
+	if f.Size > 0 && strings.TrimPrefix(f.Type, "*") == "string" {
+		rules = append(rules, "max="+strconv.Itoa(f.Size))
+	}
+	if len(rules) > 0 && strings.HasPrefix(f.Type, "*") {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+	return ` validate:"` + strings.Join(rules, ",") + `"`
+}
+
+func (m *crudModel) controller(pkg, modelImport, serviceImport string) []byte {
+	var info, create, update, filter bytes.Buffer
+	var used []crudField
+	for _, f := range m.Fields {
+		if f.Type == "gorm.DeletedAt" || f.Name == "DeletedAt" {
+			continue
+		}
+		info.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"` //%s\n", f.Name, f.Type, f.Json, f.Comment))
+		used = append(used, f)
+		if f.auto() {
+			continue
+		}
+		field := fmt.Sprintf("\t%s %s `json:\"%s\"%s` //%s\n", f.Name, f.Type, f.Json, f.validate(), f.Comment)
+		create.WriteString(field)
+		if !f.PrimaryKey {
+			update.WriteString(field)
+		}
+	}
+	for _, f := range m.Filter {
+		filter.WriteString(fmt.Sprintf("\t%s %s `form:\"%s\"` //%s\n", f.Name, f.Type, f.Json, f.Comment))
+	}
+	key := fmt.Sprintf("\t%s %s `uri:\"id\" validate:\"required\"` //%s\n", m.Key.Name, m.Key.Type, m.Key.Comment)
+	std, other := m.imports(used)
+	return []byte(fmt.Sprintf(crudControllerTemplate, pkg, modelImport, serviceImport, std, other, m.Name,
+		info.String(), filter.String(), create.String(), key+update.String(), key, m.Key.Name))
+}
+
+// imports 请求返回体字段引用的模型文件导入 返回标准库及第三方包导入
+func (m *crudModel) imports(fields []crudField) (std, other string) {
+	used := make(map[string]bool)
+	for _, f := range fields {
+		typ := strings.TrimLeft(f.Type, "*[]")
+		pkg, _, ok := strings.Cut(typ, ".")
+		if !ok || used[pkg] || m.Imports[pkg] == "" {
+			continue
+		}
+		used[pkg] = true
+		if strings.Contains(m.Imports[pkg], ".") {
+			other += "\t" + m.Imports[pkg] + "\n"
+		} else {
+			std += "\t" + m.Imports[pkg] + "\n"
+		}
+	}
+	if std != "" {
+		std += "\n"
+	}
+	return std, other
+}
+
+func (m *crudModel) service(modelImport, queryImport string) []byte {
+	var filter bytes.Buffer
+	for _, f := range m.Filter {
+		cond := "Eq"
+		if f.QueryType == "field.Bool" {
+			cond = "Is"
+		}
+		filter.WriteString(fmt.Sprintf("\tif filter.%s != nil {\n\t\tconds = append(conds, s.%s.%s.%s(*filter.%s))\n\t}\n", f.Name, m.Name, f.Name, cond, f.Name))
+	}
+	return []byte(fmt.Sprintf(crudServiceTemplate, modelImport, queryImport, m.Name, filter.String(), m.Key.Name, m.Key.Type))
+}
+
+// 参数 1包名 2model导入 3service导入 4标准库导入 5第三方导入 6模型名 7返回字段 8过滤字段 9新增字段 10更新字段 11主键字段 12主键名
+const crudControllerTemplate = `package %[1]s
+
+import (
+%[4]s	%[2]s
+	%[3]s
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+%[5]s)
+
+// %[6]s %[6]s接口 依赖*service.%[6]s 通过config.Container提供
+type %[6]s struct {
+	ctl.ControllerContext
+	Service *service.%[6]s
+}
+
+// %[6]sInfo %[6]s信息
+type %[6]sInfo struct {
+%[7]s}
+
+// List%[6]sReq %[6]s列表查询
+type List%[6]sReq struct {
+	ctl.Paging
+%[8]s}
+
+// Create%[6]sReq 创建%[6]s
+type Create%[6]sReq struct {
+%[9]s}
+
+// Update%[6]sReq 更新%[6]s
+type Update%[6]sReq struct {
+%[10]s}
+
+// %[6]sIdReq %[6]s编号
+type %[6]sIdReq struct {
+%[11]s}
+
+// List %[6]s列表
+// @GET
+func (t *%[6]s) List(ctx ctl.ControllerContext, req *List%[6]sReq) (*ctl.PageList[%[6]sInfo], error) {
+	list, total, err := t.Service.List(ctx.GetContext(), ctl.Copy(&model.%[6]s{}, req), req.Offset(), req.Size())
+	if err != nil {
+		return nil, err
+	}
+	return &ctl.PageList[%[6]sInfo]{Total: total, List: *ctl.Copy(&[]%[6]sInfo{}, list)}, nil
+}
+
+// Get %[6]s详情
+// @GET|{id}
+func (t *%[6]s) Get(ctx ctl.ControllerContext, req *%[6]sIdReq) (*%[6]sInfo, error) {
+	m, err := t.Service.Get(ctx.GetContext(), req.%[12]s)
+	if err != nil {
+		return nil, err
+	}
+	return ctl.Copy(&%[6]sInfo{}, m), nil
+}
+
+// Create 创建%[6]s
+// @POST
+func (t *%[6]s) Create(ctx ctl.ControllerContext, req *Create%[6]sReq) (*%[6]sInfo, error) {
+	m := ctl.Copy(&model.%[6]s{}, req)
+	if err := t.Service.Create(ctx.GetContext(), m); err != nil {
+		return nil, err
+	}
+	return ctl.Copy(&%[6]sInfo{}, m), nil
+}
+
+// Update 更新%[6]s
+// @PUT|{id}
+func (t *%[6]s) Update(ctx ctl.ControllerContext, req *Update%[6]sReq) (bool, error) {
+	err := t.Service.Update(ctx.GetContext(), ctl.Copy(&model.%[6]s{}, req))
+	return err == nil, err
+}
+
+// Delete 删除%[6]s
+// @DELETE|{id}
+func (t *%[6]s) Delete(ctx ctl.ControllerContext, req *%[6]sIdReq) (bool, error) {
+	err := t.Service.Delete(ctx.GetContext(), req.%[12]s)
+	return err == nil, err
+}
+`
+
+// 参数 1model导入 2query导入 3模型名 4过滤条件 5主键名 6主键类型
+const crudServiceTemplate = `package service
+
+import (
+	"context"
+
+	%[1]s
+	%[2]s
+
+	"gorm.io/gen"
+)
+
+// %[3]s %[3]s服务
+type %[3]s struct {
+	*query.Query
+}
+
+func New%[3]s(q *query.Query) *%[3]s {
+	return &%[3]s{Query: q}
+}
+
+// List 分页查询 filter中不为nil的可空字段作为过滤条件
+func (s *%[3]s) List(ctx context.Context, filter *model.%[3]s, offset, limit int) ([]*model.%[3]s, int64, error) {
+	var conds []gen.Condition
+%[4]s	return s.Get%[3]sDao(ctx).Where(conds...).Order(s.%[3]s.%[5]s.Desc()).FindByPage(offset, limit)
+}
+
+// Get 按主键查询
+func (s *%[3]s) Get(ctx context.Context, id %[6]s) (*model.%[3]s, error) {
+	return s.Get%[3]sDao(ctx).Where(s.%[3]s.%[5]s.Eq(id)).First()
+}
+
+// Create 新增
+func (s *%[3]s) Create(ctx context.Context, m *model.%[3]s) error {
+	return s.Get%[3]sDao(ctx).Create(m)
+}
+
+// Update 按主键更新非零值字段
+func (s *%[3]s) Update(ctx context.Context, m *model.%[3]s) error {
+	_, err := s.Get%[3]sDao(ctx).Where(s.%[3]s.%[5]s.Eq(m.%[5]s)).Updates(m)
+	return err
+}
+
+// Delete 按主键删除
+func (s *%[3]s) Delete(ctx context.Context, id %[6]s) error {
+	_, err := s.Get%[3]sDao(ctx).Where(s.%[3]s.%[5]s.Eq(id)).Delete()
+	return err
+}
+`
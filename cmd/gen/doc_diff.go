@@ -0,0 +1,25 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+// DocCmdDiff 文档比较子命令 cargen doc diff old.json new.json
+const DocCmdDiff = "diff"
+
+// DocDiff 比较两个版本的文档并将报告写入w 存在破坏性变更时返回false 命令以非0状态退出
+func DocDiff(w io.Writer, oldFile, newFile string) bool {
+	if oldFile == "" || newFile == "" {
+		log.Fatal("usage: cargen doc diff old.json new.json")
+	}
+	changes, err := openapi.DiffFile(oldFile, newFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintln(w, changes.Report())
+	return !changes.HasBreaking()
+}
@@ -0,0 +1,93 @@
+package gen
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+// PermissionManifestFile 模块下的权限清单文件
+const PermissionManifestFile = "router/permission.gen.json"
+
+// PermissionManifests 按模块路径记录权限清单 生成路由的模块均有记录 没有权限时清单为空
+type PermissionManifests map[string]*PermissionManifest
+
+// PermissionManifest 权限清单 列出所有权限及需要该权限的接口
+type PermissionManifest struct {
+	Permissions []Permission `json:"permissions"`
+}
+
+type Permission struct {
+	Name   string            `json:"name"`
+	Routes []PermissionRoute `json:"routes"`
+}
+
+type PermissionRoute struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Api     string `json:"api"` //接口方法 Order.Create
+	Summary string `json:"summary,omitempty"`
+}
+
+// Add 记录接口所需权限 未声明perm:的接口仅记录模块
+func (m PermissionManifests) Add(modPath string, api openapi.Api, group string) {
+	manifest := m[modPath]
+	if manifest == nil {
+		manifest = &PermissionManifest{}
+		m[modPath] = manifest
+	}
+	if len(api.Permissions) == 0 {
+		return
+	}
+	route := PermissionRoute{Method: strings.ToUpper(api.HttpMethod), Path: api.GetRequestPath(), Api: group + "." + api.Name, Summary: api.Summary}
+	for _, name := range api.Permissions {
+		manifest.add(name, route)
+	}
+}
+
+func (m *PermissionManifest) add(name string, route PermissionRoute) {
+	for i := range m.Permissions {
+		if m.Permissions[i].Name == name {
+			m.Permissions[i].Routes = append(m.Permissions[i].Routes, route)
+			return
+		}
+	}
+	m.Permissions = append(m.Permissions, Permission{Name: name, Routes: []PermissionRoute{route}})
+}
+
+// Files 权限清单文件 router/permission.gen.json
+func (m PermissionManifests) Files() map[string]string {
+	files := make(map[string]string)
+	for modPath, manifest := range m {
+		if len(manifest.Permissions) == 0 {
+			continue
+		}
+		sort.Slice(manifest.Permissions, func(i, j int) bool {
+			return manifest.Permissions[i].Name < manifest.Permissions[j].Name
+		})
+		for _, p := range manifest.Permissions {
+			sort.Slice(p.Routes, func(i, j int) bool {
+				if p.Routes[i].Path == p.Routes[j].Path {
+					return p.Routes[i].Method < p.Routes[j].Method
+				}
+				return p.Routes[i].Path < p.Routes[j].Path
+			})
+		}
+		b, _ := json.MarshalIndent(manifest, "", "  ")
+		files[modPath+"/"+PermissionManifestFile] = string(b) + "\n"
+	}
+	return files
+}
+
+// Stale 不再声明权限的模块中遗留的权限清单文件
+func (m PermissionManifests) Stale() []string {
+	var files []string
+	for modPath, manifest := range m {
+		if len(manifest.Permissions) == 0 {
+			files = append(files, modPath+"/"+PermissionManifestFile)
+		}
+	}
+	return files
+}
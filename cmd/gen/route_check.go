@@ -0,0 +1,196 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+// RouteEntry 路由表条目 生成前校验使用
+type RouteEntry struct {
+	Method string
+	Path   string //目标框架的路径 gin为/api/user/order/:id std为/api/user/order/{id}
+	File   string //控制器文件
+	Func   string //接口方法 Order.Create
+}
+
+func (r RouteEntry) String() string {
+	return r.Method + " " + r.Path + " (" + r.File + " " + r.Func + ")"
+}
+
+// routeNode 按gin路由树规则记录每一级路径
+type routeNode struct {
+	static   map[string]*routeNode
+	param    *routeNode
+	catchAll *routeNode
+	name     string      //通配符名称 :id *path
+	owner    *RouteEntry //首个注册该通配符的路由
+	route    *RouteEntry //以该节点结束的路由
+}
+
+// CheckRoutes 校验路由表 重复路由、通配符冲突及不支持的方法 gin启动时会因此panic
+// gin1.8起静态路径与:param可以并存 同一位置通配符名称不同或*catchAll存在兄弟节点时冲突
+func CheckRoutes(routes []RouteEntry) error {
+	var errs []string
+	trees := make(map[string]*routeNode)
+	for i := range routes {
+		r := &routes[i]
+		methods := []string{r.Method}
+		if r.Method == openapi.MethodAny { //Any注册到所有方法
+			methods = openapi.APIMethods
+		} else if !supportMethod(r.Method) {
+			errs = append(errs, "unsupported method: "+r.String())
+			continue
+		}
+		for _, method := range methods {
+			if trees[method] == nil {
+				trees[method] = &routeNode{}
+			}
+			if msg := trees[method].add(r); msg != "" {
+				errs = append(errs, msg)
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New("route check failed:\n\t" + strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+func supportMethod(method string) bool {
+	for _, m := range openapi.APIMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// add 添加路由 返回冲突信息
+func (n *routeNode) add(r *RouteEntry) string {
+	segs := strings.Split(strings.Trim(r.Path, "/"), "/")
+	for i, seg := range segs {
+		switch {
+		case strings.HasPrefix(seg, "*"):
+			if i != len(segs)-1 {
+				return "catch-all must be the last segment: " + r.String()
+			}
+			if len(n.static) > 0 || n.param != nil {
+				return "catch-all " + seg + " conflicts with existing routes: " + r.String() + " conflicts with " + n.anyRoute().String()
+			}
+			if n.catchAll == nil {
+				n.catchAll = &routeNode{name: seg, owner: r}
+			} else if n.catchAll.name != seg {
+				return "wildcard " + seg + " conflicts with " + n.catchAll.name + ": " + r.String() + " conflicts with " + n.catchAll.owner.String()
+			}
+			n = n.catchAll
+		case strings.HasPrefix(seg, ":"):
+			if n.catchAll != nil {
+				return "wildcard " + seg + " conflicts with catch-all " + n.catchAll.name + ": " + r.String() + " conflicts with " + n.catchAll.owner.String()
+			}
+			if n.param == nil {
+				n.param = &routeNode{name: seg, owner: r}
+			} else if n.param.name != seg {
+				return "wildcard " + seg + " conflicts with " + n.param.name + ": " + r.String() + " conflicts with " + n.param.owner.String()
+			}
+			n = n.param
+		default:
+			if n.catchAll != nil {
+				return "path segment " + seg + " conflicts with catch-all " + n.catchAll.name + ": " + r.String() + " conflicts with " + n.catchAll.owner.String()
+			}
+			if n.static == nil {
+				n.static = make(map[string]*routeNode)
+			}
+			if n.static[seg] == nil {
+				n.static[seg] = &routeNode{owner: r}
+			}
+			n = n.static[seg]
+		}
+	}
+	if n.route != nil {
+		return "duplicate route: " + r.String() + " conflicts with " + n.route.String()
+	}
+	n.route = r
+	return ""
+}
+
+// anyRoute 节点下的路由 用于冲突提示 仅在存在子节点时调用
+func (n *routeNode) anyRoute() *RouteEntry {
+	if n.param != nil {
+		return n.param.owner
+	}
+	var keys []string
+	for key := range n.static {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return n.static[keys[0]].owner
+}
+
+// stdRegisteredAt ServeMux冲突信息中的注册位置 指向校验代码而非控制器 输出时去掉
+var stdRegisteredAt = regexp.MustCompile(` \(registered at [^)]*\)`)
+
+// CheckStdRoutes 在临时ServeMux上注册全部路由 重复或无法比较优先级的模式在启动时会panic
+// ServeMux的规则与gin不同 Any注册为不限定方法的模式 可与同路径的GET等并存
+func CheckStdRoutes(routes []RouteEntry) error {
+	var errs []string
+	mux := http.NewServeMux()
+	patterns := make(map[string]*RouteEntry)
+	for i := range routes {
+		r := &routes[i]
+		pattern := r.Path
+		if r.Method != openapi.MethodAny {
+			if !supportMethod(r.Method) {
+				errs = append(errs, "unsupported method: "+r.String())
+				continue
+			}
+			pattern = r.Method + " " + pattern
+		}
+		if msg := stdRegister(mux, pattern); msg != "" {
+			msg = strings.ReplaceAll(stdRegisteredAt.ReplaceAllString(msg, ""), "\n", " ")
+			if conflict := stdConflict(patterns, pattern, msg); conflict != nil {
+				errs = append(errs, r.String()+" conflicts with "+conflict.String()+": "+msg)
+			} else {
+				errs = append(errs, r.String()+": "+msg)
+			}
+			continue
+		}
+		patterns[pattern] = r
+	}
+	if len(errs) > 0 {
+		return errors.New("route check failed:\n\t" + strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+// stdRegister 注册模式 返回ServeMux的panic信息
+func stdRegister(mux *http.ServeMux, pattern string) (msg string) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			msg = fmt.Sprint(rec)
+		}
+	}()
+	mux.Handle(pattern, http.NotFoundHandler())
+	return ""
+}
+
+// stdConflict 从冲突信息中找到已注册的路由
+func stdConflict(patterns map[string]*RouteEntry, pattern, msg string) *RouteEntry {
+	var keys []string
+	for key := range patterns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.Contains(msg, `pattern "`+key+`"`) && (key != pattern || strings.Count(msg, `"`+key+`"`) > 1) {
+			return patterns[key]
+		}
+	}
+	return nil
+}
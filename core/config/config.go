@@ -167,4 +167,7 @@ type Token struct {
 	Typ        string `yaml:"type"`   //鉴权类型
 	AuthTo     string `yaml:"authTo"` //鉴权所属 比如鉴权类型JWT，鉴权用户User JWT:User
 	Expire     int64  `yaml:"expire"` //过期时间秒
+
+	TokenUrl         string `yaml:"tokenUrl"`         //OAuth2获取token地址 用于文档
+	AuthorizationUrl string `yaml:"authorizationUrl"` //OAuth2授权地址 用于文档
 }
@@ -0,0 +1,27 @@
+package ctl
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindRequest 非gin框架按标签绑定参数 规则与GinControllerContext.Bind一致 uri为路径参数 key为绑定缓存键
+func bindRequest(req *http.Request, uri map[string][]string, key any, params any, opts []BindOption) {
+	var bindings map[BindOption]binding.Binding
+	if len(opts) == 0 {
+		bindings = constructor.GetBinding(key, params)
+	} else {
+		bindings = constructor.GetBindingByOptions(opts)
+	}
+	for _, bind := range bindings {
+		var err error
+		if bind == nil {
+			err = binding.Uri.BindUri(uri, params)
+		} else {
+			err = bind.Bind(req, params)
+		}
+		checkBindError(err)
+	}
+	validateParams(params)
+}
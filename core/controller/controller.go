@@ -6,6 +6,7 @@ import (
 
 	e "github.com/carlos-yuan/cargen/core/error"
 	"github.com/carlos-yuan/cargen/util/log"
+	"github.com/carlos-yuan/cargen/util/timeUtil"
 	"github.com/jinzhu/copier"
 )
 
@@ -113,6 +114,33 @@ func (r *Result) Err(args ...any) *Result {
 	return r
 }
 
+// Handle 类型化接口 func(ctx, *Req) (Rsp, error) 绑定并校验参数后调用 错误转换为统一返回体
+func Handle[Req any, Rsp any](ctx ControllerContext, handle func(ControllerContext, *Req) (Rsp, error)) *Result {
+	req := new(Req)
+	ctx.Bind(req)
+	rsp, err := handle(ctx, req)
+	return Return(ctx, rsp, err)
+}
+
+// HandleNoParams 无参数的类型化接口 func(ctx) (Rsp, error)
+func HandleNoParams[Rsp any](ctx ControllerContext, handle func(ControllerContext) (Rsp, error)) *Result {
+	rsp, err := handle(ctx)
+	return Return(ctx, rsp, err)
+}
+
+// Return 类型化接口返回值转换为统一返回体
+func Return(ctx ControllerContext, data any, err error) *Result {
+	if err != nil {
+		var res Result
+		var ce e.Err
+		if errors.As(err, &ce) { //包裹的错误码
+			err = ce
+		}
+		return res.Err(err)
+	}
+	return ctx.Success(data)
+}
+
 func Copy[T any](to T, from any, opts ...copier.Option) T {
 	if len(opts) > 0 {
 		err := copier.CopyWithOption(to, from, opts[0])
@@ -129,3 +157,39 @@ func Copy[T any](to T, from any, opts ...copier.Option) T {
 	}
 	return to
 }
+
+// success 成功返回 设置了加密方法时加密数据
+func success(c ControllerContext, encryption func(ctx ControllerContext, data any) (any, error), data any) *Result {
+	var res Result
+	if encryption != nil {
+		var err error
+		res.Data, err = encryption(c, data)
+		if err != nil {
+			res.Err(err)
+			return &res
+		}
+	} else {
+		res.Data = data
+	}
+	res.Code = 0
+	return &res
+}
+
+// verifyToken 校验token并返回副本 该方法出错会panic 并被panic handler拦截
+func verifyToken(c ControllerContext, tk Token) Token {
+	if tk == nil {
+		println("----------token instance not set")
+		panic(e.AuthorizeError.SetErr(errors.New("token instance not set")))
+	}
+	token := tk.Clone()
+	err := token.Verify(c)
+	if err != nil {
+		println("----------", err.Error())
+		panic(e.AuthorizeError.SetErr(err))
+	}
+	pl := token.GetPayLoad()
+	if pl.Expire() < timeUtil.Milli() {
+		panic(e.AuthorizeTimeOutError)
+	}
+	return token
+}
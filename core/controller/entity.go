@@ -1,7 +1,37 @@
 package ctl
 
+const (
+	DefaultPageSize = 20   //未传limit时的单页数量
+	MaxPageSize     = 1000 //单页数量上限
+)
+
 type Paging struct {
 	Page  int64 `form:"page"`
 	Limit int64 `form:"limit"`
 	Order int32 `form:"order"`
 }
+
+// Offset 分页偏移 页码从1开始
+func (p Paging) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+	return int((p.Page - 1) * int64(p.Size()))
+}
+
+// Size 单页数量 未传时为DefaultPageSize 不超过MaxPageSize
+func (p Paging) Size() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageSize
+	case p.Limit > MaxPageSize:
+		return MaxPageSize
+	}
+	return int(p.Limit)
+}
+
+// PageList 分页列表
+type PageList[T any] struct {
+	Total int64 `json:"total"` //总数
+	List  []T   `json:"list"`  //列表
+}
@@ -5,13 +5,13 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"path"
 	"reflect"
 	"strings"
 	"sync"
 
 	"github.com/carlos-yuan/cargen/core/config"
 	e "github.com/carlos-yuan/cargen/core/error"
-	"github.com/carlos-yuan/cargen/util/timeUtil"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -58,19 +58,7 @@ func (c *GinControllerContext) SetEncryption(f func(ctx ControllerContext, data
 }
 
 func (c *GinControllerContext) Success(data any) *Result {
-	var res Result
-	if c.Encryption != nil {
-		var err error
-		res.Data, err = c.Encryption(c, data)
-		if err != nil {
-			res.Err(err)
-			return &res
-		}
-	} else {
-		res.Data = data
-	}
-	res.Code = 0
-	return &res
+	return success(c, c.Encryption, data)
 }
 
 func (c *GinControllerContext) Bind(params any, opts ...BindOption) {
@@ -87,14 +75,20 @@ func (c *GinControllerContext) Bind(params any, opts ...BindOption) {
 		} else {
 			err = c.Context.ShouldBindWith(params, bind)
 		}
-		if err != nil && err.Error() == "EOF" {
-			err = nil
-			continue
-		}
-		if err != nil {
-			panic(e.ParamsDealError.SetErr(err, "参数获取失败"))
-		}
+		checkBindError(err)
+	}
+	validateParams(params)
+}
+
+// checkBindError 绑定出错时panic 空请求体忽略
+func checkBindError(err error) {
+	if err != nil && err.Error() != "EOF" {
+		panic(e.ParamsDealError.SetErr(err, "参数获取失败"))
 	}
+}
+
+// validateParams 参数校验 该方法出错会panic
+func validateParams(params any) {
 	if err := validate.Struct(params); err != nil {
 		errs, ok := err.(validator.ValidationErrors)
 		if ok {
@@ -108,20 +102,7 @@ func (c *GinControllerContext) Bind(params any, opts ...BindOption) {
 }
 
 func (c *GinControllerContext) CheckToken(tk Token) {
-	if tk == nil {
-		println("----------token instance not set")
-		panic(e.AuthorizeError.SetErr(errors.New("token instance not set")))
-	}
-	c.token = tk.Clone()
-	err := c.token.Verify(c)
-	if err != nil {
-		println("----------", err.Error())
-		panic(e.AuthorizeError.SetErr(err))
-	}
-	pl := c.token.GetPayLoad()
-	if pl.Expire() < timeUtil.Milli() {
-		panic(e.AuthorizeTimeOutError)
-	}
+	c.token = verifyToken(c, tk)
 }
 
 func (c *GinControllerContext) GetToken() Payload {
@@ -173,12 +154,12 @@ var constructor = &bindConstructor{}
 
 // bindConstructor gin参数绑定构造器
 type bindConstructor struct {
-	cache map[string]map[BindOption]binding.Binding
+	cache map[any]map[BindOption]binding.Binding //键为请求方法加路由 或参数类型
 	mux   sync.RWMutex
 }
 
 func (e *bindConstructor) GetBindingByOptions(opts []BindOption) map[BindOption]binding.Binding {
-	var bds map[BindOption]binding.Binding
+	bds := make(map[BindOption]binding.Binding, len(opts))
 	for _, opt := range opts {
 		bds[opt] = e.GetBindingByOption(opt)
 	}
@@ -205,12 +186,12 @@ func (e *bindConstructor) GetBindingByOption(opt BindOption) binding.Binding {
 	return nil
 }
 
-func (e *bindConstructor) GetBinding(path string, d any) map[BindOption]binding.Binding {
-	bs := e.getBinding(path)
+func (e *bindConstructor) GetBinding(key any, d any) map[BindOption]binding.Binding {
+	bs := e.getBinding(key)
 	if bs == nil {
 		//重新构建
 		bs = e.resolve(d)
-		e.setBinding(path, bs)
+		e.setBinding(key, bs)
 	}
 	return bs
 }
@@ -260,17 +241,17 @@ func (e *bindConstructor) resolve(d any) map[BindOption]binding.Binding {
 	return bs
 }
 
-func (e *bindConstructor) getBinding(name string) map[BindOption]binding.Binding {
-	return e.cache[name]
+func (e *bindConstructor) getBinding(key any) map[BindOption]binding.Binding {
+	return e.cache[key]
 }
 
-func (e *bindConstructor) setBinding(name string, bs map[BindOption]binding.Binding) {
+func (e *bindConstructor) setBinding(key any, bs map[BindOption]binding.Binding) {
 	e.mux.Lock()
 	defer e.mux.Unlock()
 	if e.cache == nil {
-		e.cache = make(map[string]map[BindOption]binding.Binding)
+		e.cache = make(map[any]map[BindOption]binding.Binding)
 	}
-	e.cache[name] = bs
+	e.cache[key] = bs
 }
 
 const defaultMemory = 32 << 20
@@ -340,28 +321,121 @@ func (GinFormMultipartFileBinding) Bind(req *http.Request, ptr any) error {
 	return nil
 }
 
+// GinBytes 输出二进制返回 成功时才设置contentType 出错时按json输出
+func GinBytes(ctx *gin.Context, res *Result, contentType string) {
+	b, ok := res.Data.([]byte)
+	if res.Code != 0 || !ok {
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+	ctx.Data(http.StatusOK, bytesContentType(contentType), b)
+}
+
+// MethodAny 注册到所有方法
+const MethodAny = "ANY"
+
 type GinRegister struct {
 	Method  string
+	Group   string //路由分组 如/api/user/v2/ 为空时注册到根路由
+	Path    string //分组内路径
+	Meta    RouteMeta
+	Handles []gin.HandlerFunc
+}
+
+// RouteMeta 路由元信息 生成时写入 用于运维查看及网关同步
+type RouteMeta struct {
+	Controller  string   `json:"controller"`
+	Func        string   `json:"func"`
+	Auth        string   `json:"auth,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	File        string   `json:"file,omitempty"` //控制器文件 相对项目目录
+}
+
+// RouteInfo 路由及元信息
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	RouteMeta
+}
+
+// DebugRoutesPath 路由查看地址
+const DebugRoutesPath = "/debug/routes"
+
+// Routes 完整路由列表
+func (r GinRegisterList) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(r))
+	for _, handler := range r {
+		routes = append(routes, routeInfo(handler.Method, handler.Group, handler.Path, handler.Meta))
+	}
+	return routes
+}
+
+func routeInfo(method, group, relativePath string, meta RouteMeta) RouteInfo {
+	return RouteInfo{Method: method, Path: path.Join("/", group, relativePath), RouteMeta: meta}
+}
+
+// DebugRoutes 注册/debug/routes 以json输出路由及元信息 仅dev和test环境注册
+func (r GinRegisterList) DebugRoutes(g *gin.Engine, env string) {
+	if env != config.EnvDev && env != config.EnvTest {
+		return
+	}
+	g.GET(DebugRoutesPath, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, r.Routes())
+	})
+}
+
+// GinGroup 分组共享中间件 路由分组以Path开头时挂载到该分组下
+type GinGroup struct {
 	Path    string
 	Handles []gin.HandlerFunc
 }
 
 type GinRegisterList []GinRegister
 
-// 加载路由
-func (r GinRegisterList) LoadRoute(g *gin.Engine) {
+// GinMiddlewares 具名路由中间件 通过config.Container提供 接口注解mw:audit,limit引用
+//
+//	config.Container.Provide(func() ctl.GinMiddlewares {
+//		return ctl.GinMiddlewares{"audit": ginmid.Audit(), "limit": ginmid.Sentinel()}
+//	})
+type GinMiddlewares map[string]gin.HandlerFunc
+
+// Get 获取中间件 未注册时panic 启动时即可发现
+func (m GinMiddlewares) Get(name string) gin.HandlerFunc {
+	h, ok := m[name]
+	if !ok || h == nil {
+		panic("gin middleware " + name + " not registered")
+	}
+	return h
+}
+
+// 加载路由 按Group分组注册 groups为共享中间件的上级分组 取最长匹配
+func (r GinRegisterList) LoadRoute(g *gin.Engine, groups ...GinGroup) {
+	routerGroups := make(map[string]*gin.RouterGroup)
 	for _, handler := range r {
-		switch handler.Method {
-		case http.MethodGet:
-			g.GET(handler.Path, handler.Handles...)
-		case http.MethodPost:
-			g.POST(handler.Path, handler.Handles...)
-		case http.MethodPut:
-			g.PUT(handler.Path, handler.Handles...)
-		case http.MethodPatch:
-			g.PATCH(handler.Path, handler.Handles...)
-		case http.MethodDelete:
-			g.DELETE(handler.Path, handler.Handles...)
+		rg, ok := routerGroups[handler.Group]
+		if !ok {
+			rg = routerGroup(g, handler.Group, groups)
+			routerGroups[handler.Group] = rg
+		}
+		if handler.Method == MethodAny {
+			rg.Any(handler.Path, handler.Handles...)
+		} else {
+			rg.Handle(handler.Method, handler.Path, handler.Handles...)
 		}
 	}
 }
+
+// routerGroup 创建路由分组 存在匹配的共享分组时作为其子分组
+func routerGroup(g *gin.Engine, path string, groups []GinGroup) *gin.RouterGroup {
+	var parent *GinGroup
+	for i := range groups {
+		if strings.HasPrefix(path, groups[i].Path) && (parent == nil || len(groups[i].Path) > len(parent.Path)) {
+			parent = &groups[i]
+		}
+	}
+	if parent == nil {
+		return g.Group(path)
+	}
+	return g.Group(parent.Path, parent.Handles...).Group(strings.TrimPrefix(path, parent.Path))
+}
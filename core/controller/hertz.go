@@ -0,0 +1,191 @@
+//go:build hertz
+
+// hertz实现 需引入github.com/cloudwego/hertz 并使用-tags hertz编译
+
+package ctl
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/carlos-yuan/cargen/core/config"
+	e "github.com/carlos-yuan/cargen/core/error"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/adaptor"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+var _ ControllerContext = &HertzControllerContext{}
+
+// HertzRequest hertz请求 Context为请求级上下文 Request为请求体
+type HertzRequest struct {
+	context.Context
+	Request *app.RequestContext
+}
+
+func NewHertzRequest(ctx context.Context, rc *app.RequestContext) HertzRequest {
+	return HertzRequest{Context: ctx, Request: rc}
+}
+
+// HertzControllerContext hertz 请求体接口实现
+type HertzControllerContext struct {
+	ctx        context.Context
+	rc         *app.RequestContext
+	conf       *config.Web
+	token      Token
+	Encryption func(ctx ControllerContext, data any) (any, error)
+}
+
+func NewHertzContext(conf *config.Web) *HertzControllerContext {
+	return &HertzControllerContext{conf: conf}
+}
+
+// SetContext ctx需为HertzRequest
+func (c HertzControllerContext) SetContext(ctx context.Context) ControllerContext {
+	r := ctx.(HertzRequest)
+	c.ctx, c.rc = r.Context, r.Request
+	return &c
+}
+
+func (c *HertzControllerContext) GetContext() context.Context {
+	return c.ctx
+}
+
+// RequestContext hertz原始请求体
+func (c *HertzControllerContext) RequestContext() *app.RequestContext {
+	return c.rc
+}
+
+func (c *HertzControllerContext) GetRequestInfo() (method, url string) {
+	return string(c.rc.Method()), c.rc.FullPath()
+}
+
+func (c *HertzControllerContext) SetEncryption(f func(ctx ControllerContext, data any) (any, error)) {
+	c.Encryption = f
+}
+
+func (c *HertzControllerContext) Success(data any) *Result {
+	return success(c, c.Encryption, data)
+}
+
+// Bind 转换为http.Request后按gin相同规则绑定 路径参数来自hertz路由
+func (c *HertzControllerContext) Bind(params any, opts ...BindOption) {
+	req, err := adaptor.GetCompatRequest(&c.rc.Request)
+	if err != nil {
+		panic(e.ParamsDealError.SetErr(err, "参数获取失败"))
+	}
+	uri := make(map[string][]string, len(c.rc.Params))
+	for _, p := range c.rc.Params {
+		uri[p.Key] = []string{p.Value}
+	}
+	bindRequest(req, uri, string(c.rc.Method())+c.rc.FullPath(), params, opts)
+}
+
+func (c *HertzControllerContext) CheckToken(tk Token) {
+	c.token = verifyToken(c, tk)
+}
+
+func (c *HertzControllerContext) GetToken() Payload {
+	if c.token == nil {
+		return nil
+	}
+	return c.token.GetPayLoad()
+}
+
+func (c *HertzControllerContext) GetHeader(key string) string {
+	return string(c.rc.GetHeader(key))
+}
+
+func (c *HertzControllerContext) SetHeader(key, val string) {
+	c.rc.Header(key, val)
+}
+
+// HertzBytes 输出二进制返回 成功时才设置contentType 出错时按json输出
+func HertzBytes(rc *app.RequestContext, res *Result, contentType string) {
+	b, ok := res.Data.([]byte)
+	if res.Code != 0 || !ok {
+		rc.JSON(http.StatusOK, res)
+		return
+	}
+	rc.SetContentType(bytesContentType(contentType))
+	rc.SetStatusCode(http.StatusOK)
+	_, _ = rc.Write(b)
+}
+
+type HertzRegister struct {
+	Method  string
+	Group   string //路由分组 如/api/user/v2/ 为空时注册到根路由
+	Path    string //分组内路径
+	Meta    RouteMeta
+	Handles []app.HandlerFunc
+}
+
+// HertzGroup 分组共享中间件 路由分组以Path开头时挂载到该分组下
+type HertzGroup struct {
+	Path    string
+	Handles []app.HandlerFunc
+}
+
+type HertzRegisterList []HertzRegister
+
+// HertzMiddlewares 具名路由中间件 通过config.Container提供 接口注解mw:audit,limit引用
+type HertzMiddlewares map[string]app.HandlerFunc
+
+// Get 获取中间件 未注册时panic 启动时即可发现
+func (m HertzMiddlewares) Get(name string) app.HandlerFunc {
+	h, ok := m[name]
+	if !ok || h == nil {
+		panic("hertz middleware " + name + " not registered")
+	}
+	return h
+}
+
+// 加载路由 按Group分组注册 groups为共享中间件的上级分组 取最长匹配
+func (r HertzRegisterList) LoadRoute(h *route.Engine, groups ...HertzGroup) {
+	routerGroups := make(map[string]*route.RouterGroup)
+	for _, handler := range r {
+		rg, ok := routerGroups[handler.Group]
+		if !ok {
+			rg = hertzRouterGroup(h, handler.Group, groups)
+			routerGroups[handler.Group] = rg
+		}
+		if handler.Method == MethodAny {
+			rg.Any(handler.Path, handler.Handles...)
+		} else {
+			rg.Handle(handler.Method, handler.Path, handler.Handles...)
+		}
+	}
+}
+
+func hertzRouterGroup(h *route.Engine, path string, groups []HertzGroup) *route.RouterGroup {
+	var parent *HertzGroup
+	for i := range groups {
+		if strings.HasPrefix(path, groups[i].Path) && (parent == nil || len(groups[i].Path) > len(parent.Path)) {
+			parent = &groups[i]
+		}
+	}
+	if parent == nil {
+		return h.Group(path)
+	}
+	return h.Group(parent.Path, parent.Handles...).Group(strings.TrimPrefix(path, parent.Path))
+}
+
+// Routes 完整路由列表
+func (r HertzRegisterList) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(r))
+	for _, handler := range r {
+		routes = append(routes, routeInfo(handler.Method, handler.Group, handler.Path, handler.Meta))
+	}
+	return routes
+}
+
+// DebugRoutes 注册/debug/routes 以json输出路由及元信息 仅dev和test环境注册
+func (r HertzRegisterList) DebugRoutes(h *route.Engine, env string) {
+	if env != config.EnvDev && env != config.EnvTest {
+		return
+	}
+	h.GET(DebugRoutesPath, func(ctx context.Context, rc *app.RequestContext) {
+		rc.JSON(http.StatusOK, r.Routes())
+	})
+}
@@ -0,0 +1,39 @@
+package ctl
+
+import (
+	"errors"
+	"strings"
+
+	e "github.com/carlos-yuan/cargen/core/error"
+)
+
+// PermissionChecker 权限校验 通过config.Container提供 接口注解perm:order.read,order.write引用
+//
+//	config.Container.Provide(func() ctl.PermissionChecker {
+//		return ctl.PermissionFunc(func(ctx ctl.ControllerContext, payload ctl.Payload, perms []string) (bool, error) {...})
+//	})
+type PermissionChecker interface {
+	// HasPermission 是否同时具备perms中的权限 payload为CheckToken后的token内容 未鉴权时为nil
+	HasPermission(ctx ControllerContext, payload Payload, perms []string) (bool, error)
+}
+
+// PermissionFunc 函数形式的权限校验
+type PermissionFunc func(ctx ControllerContext, payload Payload, perms []string) (bool, error)
+
+func (f PermissionFunc) HasPermission(ctx ControllerContext, payload Payload, perms []string) (bool, error) {
+	return f(ctx, payload, perms)
+}
+
+// CheckPermission 校验接口所需权限 该方法出错会panic NoPermissionError 并被panic handler拦截返回403
+func CheckPermission(checker PermissionChecker, ctx ControllerContext, perms ...string) {
+	if checker == nil {
+		panic(e.NoPermissionError.SetErr(errors.New("permission checker not set")))
+	}
+	ok, err := checker.HasPermission(ctx, ctx.GetToken(), perms)
+	if err != nil {
+		panic(e.NoPermissionError.SetErr(err))
+	}
+	if !ok {
+		panic(e.NoPermissionError.SetErr(errors.New("permission denied: " + strings.Join(perms, ","))))
+	}
+}
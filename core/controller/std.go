@@ -0,0 +1,239 @@
+package ctl
+
+import (
+	"context"
+	stdjson "encoding/json"
+	stdxml "encoding/xml"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/carlos-yuan/cargen/core/config"
+)
+
+var _ ControllerContext = &StdControllerContext{}
+
+// StdRequest net/http请求
+type StdRequest struct {
+	context.Context
+	Writer  http.ResponseWriter
+	Request *http.Request
+}
+
+func NewStdRequest(w http.ResponseWriter, r *http.Request) StdRequest {
+	return StdRequest{Context: r.Context(), Writer: w, Request: r}
+}
+
+// StdControllerContext net/http 请求体接口实现 路径参数使用Go1.22 ServeMux的PathValue
+type StdControllerContext struct {
+	w          http.ResponseWriter
+	r          *http.Request
+	conf       *config.Web
+	token      Token
+	Encryption func(ctx ControllerContext, data any) (any, error)
+}
+
+func NewStdContext(conf *config.Web) *StdControllerContext {
+	return &StdControllerContext{conf: conf}
+}
+
+// SetContext ctx需为StdRequest
+func (c StdControllerContext) SetContext(ctx context.Context) ControllerContext {
+	r := ctx.(StdRequest)
+	c.w, c.r = r.Writer, r.Request
+	return &c
+}
+
+func (c *StdControllerContext) GetContext() context.Context {
+	return c.r.Context()
+}
+
+// Request 原始请求
+func (c *StdControllerContext) Request() *http.Request {
+	return c.r
+}
+
+// Writer 原始响应
+func (c *StdControllerContext) Writer() http.ResponseWriter {
+	return c.w
+}
+
+func (c *StdControllerContext) GetRequestInfo() (method, url string) {
+	return c.r.Method, c.r.URL.Path
+}
+
+func (c *StdControllerContext) SetEncryption(f func(ctx ControllerContext, data any) (any, error)) {
+	c.Encryption = f
+}
+
+func (c *StdControllerContext) Success(data any) *Result {
+	return success(c, c.Encryption, data)
+}
+
+// Bind 按gin相同规则绑定 uri标签从PathValue获取 绑定方式按参数类型缓存
+func (c *StdControllerContext) Bind(params any, opts ...BindOption) {
+	uri := make(map[string][]string)
+	t := reflect.TypeOf(params)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		for i := 0; i < t.Elem().NumField(); i++ {
+			name, ok := t.Elem().Field(i).Tag.Lookup("uri")
+			if !ok {
+				continue
+			}
+			name, _, _ = strings.Cut(name, ",")
+			if val := c.r.PathValue(name); val != "" {
+				uri[name] = []string{val}
+			}
+		}
+	}
+	bindRequest(c.r, uri, t, params, opts)
+}
+
+func (c *StdControllerContext) CheckToken(tk Token) {
+	c.token = verifyToken(c, tk)
+}
+
+func (c *StdControllerContext) GetToken() Payload {
+	if c.token == nil {
+		return nil
+	}
+	return c.token.GetPayLoad()
+}
+
+func (c *StdControllerContext) GetHeader(key string) string {
+	return c.r.Header.Get(key)
+}
+
+func (c *StdControllerContext) SetHeader(key, val string) {
+	c.w.Header().Set(key, val)
+}
+
+// StdJSON 输出json
+func StdJSON(w http.ResponseWriter, data any) {
+	StdStatusJSON(w, http.StatusOK, data)
+}
+
+// StdStatusJSON 按状态码输出json
+func StdStatusJSON(w http.ResponseWriter, code int, data any) {
+	stdRender(w, code, "application/json; charset=utf-8", stdjson.Marshal, data)
+}
+
+// StdXML 输出xml
+func StdXML(w http.ResponseWriter, data any) {
+	stdRender(w, http.StatusOK, "application/xml; charset=utf-8", stdxml.Marshal, data)
+}
+
+// stdRender 按编码方法输出 编码失败时返回500
+func stdRender(w http.ResponseWriter, code int, contentType string, marshal func(any) ([]byte, error), data any) {
+	b, err := marshal(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+// StdBytes 输出二进制返回 成功时才设置contentType 出错时按json输出
+func StdBytes(w http.ResponseWriter, res *Result, contentType string) {
+	b, ok := res.Data.([]byte)
+	if res.Code != 0 || !ok {
+		StdJSON(w, res)
+		return
+	}
+	w.Header().Set("Content-Type", bytesContentType(contentType))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// bytesContentType 二进制返回类型 未指定时为application/octet-stream
+func bytesContentType(contentType string) string {
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
+
+// StdHandler net/http中间件 接口处理通过StdHandle转换
+type StdHandler = func(next http.Handler) http.Handler
+
+// StdHandle 接口处理函数 作为处理链的最后一个
+func StdHandle(h http.HandlerFunc) StdHandler {
+	return func(http.Handler) http.Handler {
+		return h
+	}
+}
+
+type StdRegister struct {
+	Method  string
+	Group   string //路由分组 如/api/user/v2/ 为空时注册到根路由
+	Path    string //分组内路径 路径参数为{id}
+	Meta    RouteMeta
+	Handles []StdHandler
+}
+
+// StdGroup 分组共享中间件 路由分组以Path开头时在其路由前执行
+type StdGroup struct {
+	Path    string
+	Handles []StdHandler
+}
+
+type StdRegisterList []StdRegister
+
+// StdMiddlewares 具名路由中间件 通过config.Container提供 接口注解mw:audit,limit引用
+type StdMiddlewares map[string]StdHandler
+
+// Get 获取中间件 未注册时panic 启动时即可发现
+func (m StdMiddlewares) Get(name string) StdHandler {
+	h, ok := m[name]
+	if !ok || h == nil {
+		panic("std middleware " + name + " not registered")
+	}
+	return h
+}
+
+// 加载路由 使用Go1.22 ServeMux的"GET /api/user/{id}"模式 groups为共享中间件的上级分组 取最长匹配
+func (r StdRegisterList) LoadRoute(mux *http.ServeMux, groups ...StdGroup) {
+	for _, handler := range r {
+		var parent *StdGroup
+		for i := range groups {
+			if strings.HasPrefix(handler.Group, groups[i].Path) && (parent == nil || len(groups[i].Path) > len(parent.Path)) {
+				parent = &groups[i]
+			}
+		}
+		handles := handler.Handles
+		if parent != nil {
+			handles = append(append([]StdHandler{}, parent.Handles...), handles...)
+		}
+		var h http.Handler = http.NotFoundHandler()
+		for i := len(handles) - 1; i >= 0; i-- {
+			h = handles[i](h)
+		}
+		pattern := path.Join("/", handler.Group, handler.Path)
+		if handler.Method != MethodAny { //Any不限定方法
+			pattern = handler.Method + " " + pattern
+		}
+		mux.Handle(pattern, h)
+	}
+}
+
+// Routes 完整路由列表
+func (r StdRegisterList) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(r))
+	for _, handler := range r {
+		routes = append(routes, routeInfo(handler.Method, handler.Group, handler.Path, handler.Meta))
+	}
+	return routes
+}
+
+// DebugRoutes 注册/debug/routes 以json输出路由及元信息 仅dev和test环境注册
+func (r StdRegisterList) DebugRoutes(mux *http.ServeMux, env string) {
+	if env != config.EnvDev && env != config.EnvTest {
+		return
+	}
+	mux.HandleFunc(http.MethodGet+" "+DebugRoutesPath, func(w http.ResponseWriter, _ *http.Request) {
+		StdJSON(w, r.Routes())
+	})
+}
@@ -0,0 +1,223 @@
+package ctl
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSEHeartbeat SSE心跳间隔 防止代理断开空闲连接
+var SSEHeartbeat = 15 * time.Second
+
+// Event SSE事件 Data非字符串时按json编码
+type Event struct {
+	Id    string
+	Event string
+	Data  any
+	Retry int //重连间隔毫秒
+}
+
+// Stream 流式下载 Reader实现io.ReadSeeker时支持Range请求
+type Stream struct {
+	Reader      io.Reader
+	Name        string //下载文件名
+	ContentType string
+	Size        int64 //非ReadSeeker时用于Content-Length 0为未知
+	ModTime     time.Time
+}
+
+// GinSSE 推送SSE事件 Data支持元素为Event或任意值的通道及迭代器 如chan Event、<-chan any、iter.Seq[Event]、func(yield func(any) bool)
+// 客户端断开时停止推送 返回错误时按json输出
+func GinSSE(ctx *gin.Context, res *Result) {
+	StdSSE(ctx.Writer, ctx.Request, res)
+}
+
+// StdSSE net/http推送SSE事件 规则同GinSSE
+func StdSSE(w http.ResponseWriter, r *http.Request, res *Result) {
+	events, stop := sseEvents(res.Data)
+	if res.Code != 0 || events == nil {
+		StdJSON(w, res)
+		return
+	}
+	defer stop()
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") //nginx不缓冲
+	w.WriteHeader(http.StatusOK)
+	flush := func() {
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	flush()
+	heartbeat := time.NewTicker(SSEHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+		}
+		flush()
+	}
+}
+
+// sseEvents 统一转换为事件通道 stop用于客户端断开时结束迭代器
+// 按底层类型匹配 双向通道及iter.Seq等具名迭代器同样支持
+func sseEvents(data any) (<-chan Event, func()) {
+	done := make(chan struct{})
+	stop := func() { close(done) }
+	if d, ok := data.(<-chan Event); ok {
+		return d, stop
+	}
+	rv := reflect.ValueOf(data)
+	switch rv.Kind() {
+	case reflect.Chan:
+		if rv.IsNil() || rv.Type().ChanDir()&reflect.RecvDir == 0 {
+			return nil, stop
+		}
+		events := make(chan Event)
+		go func() {
+			defer close(events)
+			cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: rv}, {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)}}
+			for {
+				chosen, v, ok := reflect.Select(cases)
+				if chosen == 1 || !ok {
+					return
+				}
+				select {
+				case events <- toEvent(v.Interface()):
+				case <-done:
+					return
+				}
+			}
+		}()
+		return events, stop
+	case reflect.Func:
+		t := rv.Type()
+		if rv.IsNil() || t.NumIn() != 1 || t.NumOut() != 0 {
+			return nil, stop
+		}
+		yt := t.In(0) //func(yield func(T) bool)
+		if yt.Kind() != reflect.Func || yt.NumIn() != 1 || yt.NumOut() != 1 || yt.Out(0).Kind() != reflect.Bool {
+			return nil, stop
+		}
+		events := make(chan Event)
+		yield := reflect.MakeFunc(yt, func(args []reflect.Value) []reflect.Value {
+			select {
+			case events <- toEvent(args[0].Interface()):
+				return []reflect.Value{reflect.ValueOf(true).Convert(yt.Out(0))}
+			case <-done:
+				return []reflect.Value{reflect.ValueOf(false).Convert(yt.Out(0))}
+			}
+		})
+		go func() {
+			defer close(events)
+			rv.Call([]reflect.Value{yield})
+		}()
+		return events, stop
+	}
+	return nil, stop
+}
+
+func toEvent(v any) Event {
+	if ev, ok := v.(Event); ok {
+		return ev
+	}
+	return Event{Data: v}
+}
+
+func writeEvent(w io.Writer, ev Event) error {
+	var data string
+	switch d := ev.Data.(type) {
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		b, err := stdjson.Marshal(d)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+	msg := ""
+	if ev.Id != "" {
+		msg += "id: " + ev.Id + "\n"
+	}
+	if ev.Event != "" {
+		msg += "event: " + ev.Event + "\n"
+	}
+	if ev.Retry > 0 {
+		msg += "retry: " + strconv.Itoa(ev.Retry) + "\n"
+	}
+	for _, line := range strings.Split(data, "\n") { //多行数据每行一个data字段
+		msg += "data: " + line + "\n"
+	}
+	_, err := io.WriteString(w, msg+"\n")
+	return err
+}
+
+// GinStream 流式输出 Data支持*Stream、Stream、io.Reader 可Seek时支持Range断点续传
+// contentType为注解stream:text/csv指定的类型 Stream.ContentType优先 出错时按json输出
+func GinStream(ctx *gin.Context, res *Result, contentType string) {
+	StdStream(ctx.Writer, ctx.Request, res, contentType)
+}
+
+// StdStream net/http流式输出 规则同GinStream
+func StdStream(w http.ResponseWriter, r *http.Request, res *Result, contentType string) {
+	var s Stream
+	switch d := res.Data.(type) {
+	case *Stream:
+		if d != nil {
+			s = *d
+		}
+	case Stream:
+		s = d
+	case io.Reader:
+		s.Reader = d
+	}
+	if res.Code != 0 || s.Reader == nil {
+		StdJSON(w, res)
+		return
+	}
+	if closer, ok := s.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if s.ContentType == "" { //未指定时使用注解stream:text/csv设置的类型
+		s.ContentType = bytesContentType(contentType)
+	}
+	w.Header().Set("Content-Type", s.ContentType)
+	if s.Name != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": s.Name}))
+	}
+	if rs, ok := s.Reader.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, s.Name, s.ModTime, rs)
+		return
+	}
+	if s.Size > 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(s.Size))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, s.Reader)
+}
@@ -0,0 +1,110 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	e "github.com/carlos-yuan/cargen/core/error"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// WsConn websocket连接 消息按json帧编解码
+type WsConn interface {
+	// ReadJSON 读取一条消息
+	ReadJSON(v any) error
+	// WriteJSON 发送一条消息
+	WriteJSON(v any) error
+	// Context 连接上下文 接口处理返回后结束 客户端断开时ReadJSON返回错误 需由接口自行返回
+	Context() context.Context
+	Close() error
+}
+
+// WsCheckOrigin 校验Origin 默认仅允许同源及未携带Origin的非浏览器客户端
+var WsCheckOrigin = func(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+type wsConn struct {
+	*websocket.Conn
+	ctx context.Context
+}
+
+func (c *wsConn) ReadJSON(v any) error {
+	return websocket.JSON.Receive(c.Conn, v)
+}
+
+func (c *wsConn) WriteJSON(v any) error {
+	return websocket.JSON.Send(c.Conn, v)
+}
+
+func (c *wsConn) Context() context.Context {
+	return c.ctx
+}
+
+// GinWebSocket 升级为websocket连接并交由接口处理 鉴权需在升级前完成
+// 处理返回错误时按Result发送最后一条消息后关闭连接
+func GinWebSocket(ctx *gin.Context, c ControllerContext, h func(ctx ControllerContext, conn WsConn) error) {
+	StdWebSocket(ctx.Writer, ctx.Request, c, h)
+	ctx.Abort()
+}
+
+// GinWebSocketBind 升级前绑定握手参数 func(ctx ctl.ControllerContext, conn ctl.WsConn, req *Req) error
+func GinWebSocketBind[Req any](ctx *gin.Context, c ControllerContext, h func(ctx ControllerContext, conn WsConn, req *Req) error) {
+	StdWebSocketBind(ctx.Writer, ctx.Request, c, h)
+	ctx.Abort()
+}
+
+// StdWebSocket net/http升级websocket连接 规则同GinWebSocket
+func StdWebSocket(w http.ResponseWriter, r *http.Request, c ControllerContext, h func(ctx ControllerContext, conn WsConn) error) {
+	if !WsCheckOrigin(r) {
+		panic(e.AuthorizeError.SetErr(errors.New("websocket origin not allowed")))
+	}
+	websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil }, //Origin已校验
+		Handler: func(ws *websocket.Conn) {
+			wctx, cancel := context.WithCancel(r.Context())
+			conn := &wsConn{Conn: ws, ctx: wctx}
+			defer func() {
+				cancel()
+				_ = conn.Close()
+			}()
+			if err := wsHandle(c, conn, h); err != nil {
+				var res Result
+				res.Err(err)
+				_ = conn.WriteJSON(res)
+			}
+		},
+	}.ServeHTTP(w, r)
+}
+
+// StdWebSocketBind 升级前绑定握手参数
+func StdWebSocketBind[Req any](w http.ResponseWriter, r *http.Request, c ControllerContext, h func(ctx ControllerContext, conn WsConn, req *Req) error) {
+	var req Req
+	c.Bind(&req)
+	StdWebSocket(w, r, c, func(c ControllerContext, conn WsConn) error {
+		return h(c, conn, &req)
+	})
+}
+
+// wsHandle 连接已升级 panic无法再由中间件输出 转为错误返回
+func wsHandle(c ControllerContext, conn WsConn, h func(ctx ControllerContext, conn WsConn) error) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if er, ok := rec.(error); ok {
+				err = er
+			} else {
+				err = fmt.Errorf("%v", rec)
+			}
+		}
+	}()
+	return h(c, conn)
+}
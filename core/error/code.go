@@ -1,6 +1,7 @@
 package e
 
 import (
+	"net/http"
 	"strconv"
 	"sync"
 )
@@ -62,6 +63,30 @@ func SetCodeNameMap(code int, name string) {
 	codeNameMutex.Unlock()
 }
 
+// CodeNames 获取所有已注册的错误码名称
+func CodeNames() map[int]string {
+	codeNameMutex.Lock()
+	defer codeNameMutex.Unlock()
+	names := make(map[int]string, len(codeNameMap))
+	for k, v := range codeNameMap {
+		names[k] = v
+	}
+	return names
+}
+
+// HttpStatus 错误码对应的http状态码
+func HttpStatus(code int) int {
+	switch code {
+	case ParamsDealErrorCode, ParamsValidatorErrorCode:
+		return http.StatusBadRequest
+	case AuthorizeErrorCode, AuthorizeTimeOutErrorCode:
+		return http.StatusUnauthorized
+	case NoPermissionErrorCode:
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
 func (e Err) CodeName() string {
 	for k, v := range codeNameMap {
 		if e.Code == k {
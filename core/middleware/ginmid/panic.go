@@ -1,6 +1,7 @@
 package ginmid
 
 import (
+	"net/http"
 	"runtime/debug"
 
 	e "github.com/carlos-yuan/cargen/core/error"
@@ -15,16 +16,16 @@ func Panic() gin.HandlerFunc {
 			if rec := recover(); rec != nil {
 				err, ok := rec.(e.Err)
 				if ok {
-					switch err.Code {
-					case e.AuthorizeErrorCode, e.AuthorizeTimeOutErrorCode:
+					switch e.HttpStatus(err.Code) {
+					case http.StatusUnauthorized:
 						err.Msg = "尚未授权"
-						c.JSON(401, err)
+						c.JSON(http.StatusUnauthorized, err)
 						return
-					case e.ParamsDealErrorCode, e.ParamsValidatorErrorCode:
-						c.JSON(400, err)
+					case http.StatusBadRequest:
+						c.JSON(http.StatusBadRequest, err)
 						return
-					case e.NoPermissionErrorCode:
-						c.JSON(403, err)
+					case http.StatusForbidden:
+						c.JSON(http.StatusForbidden, err)
 						return
 					}
 				}
@@ -0,0 +1,39 @@
+package stdmid
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	e "github.com/carlos-yuan/cargen/core/error"
+)
+
+// Panic 业务抛出PANIC时处理 规则同ginmid.Panic
+func Panic() ctl.StdHandler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					err, ok := rec.(e.Err)
+					if ok {
+						switch e.HttpStatus(err.Code) {
+						case http.StatusUnauthorized:
+							err.Msg = "尚未授权"
+							ctl.StdStatusJSON(w, http.StatusUnauthorized, err)
+							return
+						case http.StatusBadRequest:
+							ctl.StdStatusJSON(w, http.StatusBadRequest, err)
+							return
+						case http.StatusForbidden:
+							ctl.StdStatusJSON(w, http.StatusForbidden, err)
+							return
+						}
+					}
+					println(string(debug.Stack()))
+					ctl.StdStatusJSON(w, http.StatusInternalServerError, e.InternalServerError.SetRecover(rec))
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
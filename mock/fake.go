@@ -0,0 +1,105 @@
+package mock
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+var fakeNames = []string{"张三", "李四", "王五", "赵六", "Alice", "Bob"}
+var fakeWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "mock"}
+
+// fake 通过属性生成模拟值 优先使用示例值和枚举值
+func (s *Server) fake(name string, p openapi.Property, storey int) any {
+	p = s.resolve(p)
+	if p.Example != nil {
+		return p.Example
+	}
+	if len(p.Enum) > 0 {
+		return p.Enum[rand.Intn(len(p.Enum))]
+	}
+	if key, val, ok := enumObject(p); ok { //字典枚举对象 键和标签需要对应
+		i := rand.Intn(len(key.Enum))
+		return map[string]any{openapi.EnumKeyName: key.Enum[i], openapi.EnumValName: val.Enum[i]}
+	}
+	if len(p.Properties) > 0 || p.Type == openapi.OpenApiTypeObject {
+		m := make(map[string]any)
+		if storey > fakeDeep {
+			return m
+		}
+		for key, item := range p.Properties {
+			m[key] = s.fake(key, item, storey+1)
+		}
+		return m
+	}
+	switch p.Type {
+	case openapi.OpenApiTypeArray:
+		list := []any{}
+		if p.Items == nil || storey > fakeDeep {
+			return list
+		}
+		for i := 0; i < 1+rand.Intn(3); i++ {
+			list = append(list, s.fake(name, *p.Items, storey+1))
+		}
+		return list
+	case openapi.OpenApiTypeInteger:
+		return fakeInteger(name)
+	case openapi.OpenApiTypeNumber:
+		return math.Round(rand.Float64()*10000) / 100
+	case openapi.OpenApiTypeBoolean:
+		return rand.Intn(2) == 1
+	case openapi.OpenApiTypeString:
+		return fakeString(name)
+	}
+	return nil
+}
+
+func enumObject(p openapi.Property) (key, val openapi.Property, ok bool) {
+	if len(p.Properties) != 2 {
+		return
+	}
+	key, ok = p.Properties[openapi.EnumKeyName]
+	if !ok {
+		return
+	}
+	val, ok = p.Properties[openapi.EnumValName]
+	ok = ok && len(key.Enum) > 0 && len(key.Enum) == len(val.Enum)
+	return
+}
+
+func fakeInteger(name string) int64 {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "time") || strings.HasSuffix(lower, "at"):
+		return time.Now().Unix() - rand.Int63n(86400*30)
+	case strings.Contains(lower, "page"):
+		return 1
+	case strings.Contains(lower, "size") || strings.Contains(lower, "limit"):
+		return 10
+	}
+	return 1 + rand.Int63n(1000)
+}
+
+// fakeString 根据字段名生成字符串
+func fakeString(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "email"):
+		return fmt.Sprintf("user%d@example.com", rand.Intn(1000))
+	case strings.Contains(lower, "phone") || strings.Contains(lower, "mobile"):
+		return fmt.Sprintf("138%08d", rand.Intn(100000000))
+	case strings.Contains(lower, "url") || strings.Contains(lower, "avatar") || strings.Contains(lower, "image"):
+		return fmt.Sprintf("https://example.com/%d.png", rand.Intn(1000))
+	case strings.Contains(lower, "time") || strings.Contains(lower, "date"):
+		return time.Now().Add(-time.Duration(rand.Int63n(int64(30 * 24 * time.Hour)))).Format(time.DateTime)
+	case lower == "id" || strings.HasSuffix(lower, "id"):
+		return fmt.Sprintf("%016x", rand.Uint64())
+	case strings.Contains(lower, "name"):
+		return fakeNames[rand.Intn(len(fakeNames))]
+	}
+	return fakeWords[rand.Intn(len(fakeWords))] + " " + fakeWords[rand.Intn(len(fakeWords))]
+}
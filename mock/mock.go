@@ -0,0 +1,174 @@
+package mock
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	e "github.com/carlos-yuan/cargen/core/error"
+	openapi "github.com/carlos-yuan/cargen/open_api"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultAddr = ":8080"
+	fakeDeep    = 6 //嵌套对象生成深度
+)
+
+// Config 模拟服务配置
+type Config struct {
+	Addr      string        //监听地址
+	Latency   time.Duration //平均响应延迟 实际延迟在0.5-1.5倍之间
+	ErrorRate float64       //错误注入概率 0-1
+}
+
+// Server 通过文档生成的模拟服务 完全离线
+type Server struct {
+	api  openapi.OpenAPI
+	conf Config
+}
+
+// Run 解析目录下的接口并启动模拟服务
+func Run(base string, conf Config) error {
+	pkgs := openapi.Packages{}
+	pkgs.Init(base)
+	if conf.Addr == "" {
+		conf.Addr = DefaultAddr
+	}
+	log.Println("mock server listen on", conf.Addr)
+	return New(pkgs.GetApi(), conf).Engine().Run(conf.Addr)
+}
+
+func New(api openapi.OpenAPI, conf Config) *Server {
+	return &Server{api: api, conf: conf}
+}
+
+// Engine 注册所有接口路由
+func (s *Server) Engine() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	var paths []string
+	for path := range s.api.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for name, method := range s.api.Paths[path] {
+			s.handle(r, strings.ToUpper(name), ginPath(path), method)
+		}
+	}
+	return r
+}
+
+// handle 注册路由 路由冲突时跳过该接口
+func (s *Server) handle(r *gin.Engine, method, path string, m openapi.Method) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Println("mock skip", method, path, rec)
+		}
+	}()
+	r.Handle(method, path, s.handler(m))
+}
+
+// ginPath /user/{id}转换为/user/:id
+func ginPath(path string) string {
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segs[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+func (s *Server) handler(m openapi.Method) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.conf.Latency > 0 {
+			time.Sleep(s.conf.Latency/2 + time.Duration(rand.Int63n(int64(s.conf.Latency))))
+		}
+		if !s.authorized(c, m) {
+			err := e.AuthorizeError
+			err.Msg = "尚未授权"
+			c.JSON(http.StatusUnauthorized, err)
+			return
+		}
+		if errs := s.validate(c, m); len(errs) > 0 {
+			err := e.ParamsValidatorError
+			err.Msg = strings.Join(errs, ";")
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		if s.conf.ErrorRate > 0 && rand.Float64() < s.conf.ErrorRate {
+			err := e.InternalServerError
+			err.Msg = "mock error"
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+		rsp, ok := m.Responses["200"]
+		if !ok {
+			c.Status(http.StatusOK)
+			return
+		}
+		for contentType, content := range rsp.Content {
+			value := s.fake("", content.Schema, 0)
+			if result, ok := value.(map[string]any); ok {
+				if _, ok := result["data"]; ok { //Result 正常返回
+					result["code"] = 0
+					result["msg"] = ""
+				}
+			}
+			if strings.Contains(contentType, "json") {
+				c.JSON(http.StatusOK, value)
+			} else {
+				c.Data(http.StatusOK, contentType, []byte(fmt.Sprint(value)))
+			}
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+}
+
+// authorized 按securitySchemes检查是否携带凭证 不校验凭证内容
+func (s *Server) authorized(c *gin.Context, m openapi.Method) bool {
+	if len(m.Security) == 0 {
+		return true
+	}
+	for _, security := range m.Security {
+		for name := range security {
+			scheme := s.api.Components.SecuritySchemes[name]
+			if scheme == nil {
+				continue
+			}
+			if scheme.Type != openapi.SecurityTypeApiKey { //http及oauth2均通过Authorization携带
+				if c.GetHeader(openapi.DefaultTokenHeader) != "" {
+					return true
+				}
+				continue
+			}
+			if scheme.In == openapi.OpenApiInCookie {
+				if _, err := c.Cookie(scheme.Name); err == nil {
+					return true
+				}
+			} else if c.GetHeader(scheme.Name) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolve 解析components/schemas引用
+func (s *Server) resolve(p openapi.Property) openapi.Property {
+	for i := 0; p.Ref != "" && i < fakeDeep; i++ {
+		ref, ok := s.api.Components.Schemas[strings.TrimPrefix(p.Ref, s.api.Components.GetSchemasName())]
+		if !ok {
+			break
+		}
+		p = ref
+	}
+	return p
+}
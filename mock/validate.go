@@ -0,0 +1,166 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+	"github.com/gin-gonic/gin"
+)
+
+// validate 按文档校验请求参数 返回错误信息
+func (s *Server) validate(c *gin.Context, m openapi.Method) []string {
+	var errs []string
+	for _, param := range m.Parameters {
+		var val string
+		var ok bool
+		switch param.In {
+		case openapi.OpenApiInPath:
+			val = c.Param(param.Name)
+			ok = val != ""
+		case openapi.OpenApiInHeader:
+			val = c.GetHeader(param.Name)
+			ok = val != ""
+		case openapi.OpenApiInCookie:
+			cookie, err := c.Cookie(param.Name)
+			val, ok = cookie, err == nil
+		default:
+			val, ok = c.GetQuery(param.Name)
+			if !ok {
+				val, ok = c.GetPostForm(param.Name)
+			}
+		}
+		if !ok {
+			if param.Required {
+				errs = append(errs, param.Name+" is required")
+			}
+			continue
+		}
+		if err := s.checkString(param.Name, val, param.Schema); err != "" {
+			errs = append(errs, err)
+		}
+	}
+	content, ok := m.RequestBody.Content["application/json"]
+	if !ok {
+		return errs
+	}
+	b, _ := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(b))
+	if len(bytes.TrimSpace(b)) == 0 {
+		if len(content.Schema.Required) > 0 {
+			errs = append(errs, "request body is required")
+		}
+		return errs
+	}
+	var body any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return append(errs, "invalid json: "+err.Error())
+	}
+	return append(errs, s.checkValue("body", body, content.Schema, 0)...)
+}
+
+// checkString 校验路径、查询等字符串参数
+func (s *Server) checkString(name, val string, p openapi.Property) string {
+	p = s.resolve(p)
+	var err error
+	switch p.Type {
+	case openapi.OpenApiTypeInteger:
+		_, err = strconv.ParseInt(val, 10, 64)
+	case openapi.OpenApiTypeNumber:
+		_, err = strconv.ParseFloat(val, 64)
+	case openapi.OpenApiTypeBoolean:
+		_, err = strconv.ParseBool(val)
+	}
+	if err != nil {
+		return name + " must be " + p.Type
+	}
+	if len(p.Enum) > 0 && !inEnum(p.Enum, val) {
+		return name + " must be one of " + enumString(p.Enum)
+	}
+	return ""
+}
+
+// checkValue 校验json请求体
+func (s *Server) checkValue(name string, val any, p openapi.Property, storey int) []string {
+	p = s.resolve(p)
+	if val == nil || storey > fakeDeep {
+		return nil
+	}
+	if len(p.Properties) > 0 || p.Type == openapi.OpenApiTypeObject {
+		m, ok := val.(map[string]any)
+		if !ok {
+			return []string{name + " must be object"}
+		}
+		var errs []string
+		for _, key := range p.Required {
+			if _, ok := m[key]; !ok {
+				errs = append(errs, name+"."+key+" is required")
+			}
+		}
+		for key, item := range p.Properties {
+			if v, ok := m[key]; ok {
+				errs = append(errs, s.checkValue(name+"."+key, v, item, storey+1)...)
+			}
+		}
+		return errs
+	}
+	var ok bool
+	switch p.Type {
+	case openapi.OpenApiTypeArray:
+		list, isList := val.([]any)
+		if !isList {
+			return []string{name + " must be array"}
+		}
+		var errs []string
+		if p.Items != nil {
+			for i, item := range list {
+				errs = append(errs, s.checkValue(fmt.Sprintf("%s[%d]", name, i), item, *p.Items, storey+1)...)
+			}
+		}
+		return errs
+	case openapi.OpenApiTypeInteger:
+		var n json.Number
+		if n, ok = val.(json.Number); ok {
+			_, err := n.Int64()
+			ok = err == nil
+		}
+	case openapi.OpenApiTypeNumber:
+		_, ok = val.(json.Number)
+	case openapi.OpenApiTypeBoolean:
+		_, ok = val.(bool)
+	case openapi.OpenApiTypeString:
+		_, ok = val.(string)
+	default:
+		ok = true
+	}
+	if !ok {
+		return []string{name + " must be " + p.Type}
+	}
+	if len(p.Enum) > 0 && !inEnum(p.Enum, fmt.Sprint(val)) {
+		return []string{name + " must be one of " + enumString(p.Enum)}
+	}
+	return nil
+}
+
+func inEnum(enum []any, val string) bool {
+	for _, v := range enum {
+		if fmt.Sprint(v) == val {
+			return true
+		}
+	}
+	return false
+}
+
+func enumString(enum []any) string {
+	var list []string
+	for _, v := range enum {
+		list = append(list, fmt.Sprint(v))
+	}
+	return strings.Join(list, ",")
+}
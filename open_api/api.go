@@ -6,49 +6,121 @@ import (
 	"go/format"
 	"go/token"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/carlos-yuan/cargen/util/convert"
 )
 
 type Api struct {
-	Name         string  `json:"name"`         //接口地址
-	Summary      string  `json:"summary"`      //名称
-	Description  string  `json:"description"`  //描述
-	RequestPath  string  `json:"request_path"` //自定义路径
-	Point        string  `json:"point"`        //接口结构体对象名称
-	Group        string  `json:"group"`        //接口结构体名称
-	HttpMethod   string  `json:"method"`       //接口http方法
-	Annotate     string  `json:"annotate"`     //注释
-	Path         string  `json:"path"`         //文件地址
-	Auth         string  `json:"auth"`         //授权方式
-	AuthTo       string  `json:"authTo"`       //授权方式
-	ResponseType string  `json:"responseType"` //返回类型
-	Params       *Struct `json:"params"`       //参数 string为路径 Parameter为对象
-	Response     *Struct `json:"response"`     //返回结构体
+	Name         string   `json:"name"`         //接口地址
+	Summary      string   `json:"summary"`      //名称
+	Description  string   `json:"description"`  //描述
+	RequestPath  string   `json:"request_path"` //自定义路径
+	Point        string   `json:"point"`        //接口结构体对象名称
+	Group        string   `json:"group"`        //接口结构体名称
+	HttpMethod   string   `json:"method"`       //接口http方法
+	Annotate     string   `json:"annotate"`     //注释
+	Path         string   `json:"path"`         //文件地址
+	Auth         string   `json:"auth"`         //授权方式
+	AuthTo       string   `json:"authTo"`       //授权方式
+	ResponseType string   `json:"responseType"` //返回类型
+	ContentType  string   `json:"contentType"`  //二进制返回内容类型 bytes:image/png
+	Permissions  []string `json:"permissions"`  //所需权限
+	Middlewares  []string `json:"middlewares"`  //路由中间件 mw:audit,limit
+	Tags         []string `json:"tags"`         //自定义标签 为空时按目录生成
+	Deprecated   bool     `json:"deprecated"`   //已废弃
+	OperationId  string   `json:"operationId"`  //自定义operationId
+	Since        string   `json:"since"`        //接口版本 作为路径前缀
+	Hidden       bool     `json:"hidden"`       //不在文档中展示
+	Typed        bool     `json:"typed"`        //类型化接口 func(ctx, *Req) (Rsp, error)
+	NoParams     bool     `json:"noParams"`     //类型化接口无请求参数 func(ctx) (Rsp, error)
+	WebSocket    bool     `json:"webSocket"`    //websocket接口 func(ctx, conn ctl.WsConn[, req *Req]) error
+	Params       *Struct  `json:"params"`       //参数 string为路径 Parameter为对象
+	Response     *Struct  `json:"response"`     //返回结构体
 	sct          *Struct
 }
 
 func (a *Api) GetOperationId() string {
+	if a.OperationId != "" {
+		return a.OperationId
+	}
 	return strings.ReplaceAll(a.sct.Pkg.Path, "/", ".") + "." + a.Name + "." + a.HttpMethod
 }
 
+// GetMethodOperationId ANY展开后各方法的operationId 默认以实际方法结尾 opid:指定时追加方法保证唯一
+func (a *Api) GetMethodOperationId(method string) string {
+	if a.HttpMethod != MethodAny {
+		return a.GetOperationId()
+	}
+	if a.OperationId != "" {
+		return a.OperationId + "." + method
+	}
+	return strings.ReplaceAll(a.sct.Pkg.Path, "/", ".") + "." + a.Name + "." + method
+}
+
+// GetTags 接口标签 未通过tag:指定时使用结构体标签
+func (a *Api) GetTags() []string {
+	if len(a.Tags) > 0 {
+		return a.Tags
+	}
+	if version := a.GetVersion(); version != "" { //注解或包版本与未分版本的接口分开
+		return []string{a.sct.GetTag() + "-" + version}
+	}
+	return []string{a.sct.GetTag()}
+}
+
+// GetVersion 接口版本 注解since:v2或@GET|v2优先 未设置时使用包版本 如api/user/v2
+func (a *Api) GetVersion() string {
+	if a.Since != "" {
+		return strings.Trim(a.Since, "/")
+	}
+	if a.sct != nil && a.sct.Pkg != nil {
+		return a.sct.Pkg.Version()
+	}
+	return ""
+}
+
+// GetVersionPrefix 版本路径前缀 since:v2 返回v2/
+func (a *Api) GetVersionPrefix() string {
+	if version := a.GetVersion(); version != "" {
+		return version + "/"
+	}
+	return ""
+}
+
 func (a *Api) GetApiPath() string {
 	return a.Group + "." + a.Name
 }
 
+// modName 接口所属mod名称 对应配置中的web
+func (a *Api) modName() string {
+	modName := a.sct.Pkg.Path
+	if i := strings.Index(modName, "/"); i > 0 {
+		modName = modName[:i]
+	}
+	return modName
+}
+
+// webConfig 接口所属mod的web配置
+func (a *Api) webConfig() WebConfig {
+	if a.sct == nil || a.sct.Pkg == nil || a.sct.Pkg.config == nil {
+		return WebConfig{}
+	}
+	return a.sct.Pkg.config.Web[a.modName()]
+}
+
 func (a *Api) GetRequestPath() string {
 	prefix := "/"
-	modName := a.sct.Pkg.Path[:strings.Index(a.sct.Pkg.Path, "/")] //mod名称
-	if a.sct.Pkg.config != nil && len(a.sct.Pkg.config.Web) > 0 && a.sct.Pkg.config.Web[modName].Prefix != "" {
-		webPrefix := a.sct.Pkg.config.Web[modName].Prefix
+	modName := a.modName()
+	if webPrefix := a.webConfig().Prefix; webPrefix != "" {
 		if webPrefix[0] != '/' {
 			prefix += webPrefix
 		} else {
 			prefix = webPrefix
 		}
 	}
-	prefix += modName + "/"
+	prefix += modName + "/" + a.GetVersionPrefix()
 	name := convert.FistToLower(a.Group) + "/" + convert.FistToLower(a.Name) //结构体名+方法名
 	if a.RequestPath != "" {
 		if a.RequestPath == "-" {
@@ -74,6 +146,7 @@ func (a *Api) GetRequestPathNoGroup() string {
 
 func (a *Api) NewStruct() *Struct {
 	s := NewStruct(a.sct.Pkg)
+	s.Imports = a.sct.Imports
 	return &s
 }
 
@@ -112,6 +185,42 @@ func (a *Api) SetResponseDataType(array bool, typ string) {
 	}
 }
 
+// IsByteArrayData t.Success([]byte)纯二进制返回
+func (a *Api) IsByteArrayData() bool {
+	if a.Response == nil {
+		return false
+	}
+	for _, f := range a.Response.Fields {
+		if f.Name == "Data" && f.Type == "byte" && f.Array {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBytes 二进制返回 bytes注解或返回[]byte 文档与路由均按二进制输出
+func (a *Api) IsBytes() bool {
+	return a.ResponseType == ResponseTypeBytes || a.IsByteArrayData()
+}
+
+// IsDataAny 返回数据类型未确定 如Data any未被t.Success或签名替换
+func (a *Api) IsDataAny() bool {
+	if a.Response == nil {
+		return true
+	}
+	for _, f := range a.Response.Fields {
+		if f.Name == "Data" {
+			return f.Struct == nil && (f.Type == "" || f.Type == "any" || f.Type == "interface{}")
+		}
+	}
+	return true
+}
+
+// IsStream 流式返回或websocket 不解析返回结构体及示例
+func (a *Api) IsStream() bool {
+	return a.WebSocket || a.ResponseType == ResponseTypeSSE || a.ResponseType == ResponseTypeStream
+}
+
 func (a *Api) GetResponseData() *Struct {
 	for i, field := range a.Response.Fields {
 		if field.Name == "Data" {
@@ -124,10 +233,20 @@ func (a *Api) GetResponseData() *Struct {
 const (
 	AnnotateSplitChar = "|"
 	AuthStart         = "auth:"
+	TagStart          = "tag:"
+	MiddlewareStart   = "mw:"
+	OpIdStart         = "opid:"
+	SinceStart        = "since:"
+	SummaryStart      = "summary:"
+	DeprecatedMark    = "deprecated"
+	HiddenMark        = "hidden"
+	WebSocketMark     = "ws"
 
-	ResponseTypeJSON  = "json"
-	ResponseTypeXML   = "xml"
-	ResponseTypeBytes = "bytes"
+	ResponseTypeJSON   = "json"
+	ResponseTypeXML    = "xml"
+	ResponseTypeBytes  = "bytes"
+	ResponseTypeSSE    = "sse"    //Server-Sent Events 返回通道或迭代器
+	ResponseTypeStream = "stream" //流式下载 返回ctl.Stream
 
 	AuthTypeJWT     = "JWT"
 	AuthTypeOAuth2  = "OAuth2"
@@ -135,8 +254,21 @@ const (
 	AuthTypeCookie  = "Cookie"
 	AuthTypeSecret  = "Secret"
 
+	SecurityTypeHttp   = "http"
+	SecurityTypeApiKey = "apiKey"
+	SecurityTypeOAuth2 = "oauth2"
+
+	DefaultTokenHeader    = "Authorization"
+	DefaultTokenCookie    = "token"
+	DefaultOAuth2TokenUrl = "/oauth2/token"
+
+	MethodAny = "ANY" //注册到所有方法 对应gin的Any
+
 	ApiParamsBindMethod    = "Bind"
 	ApiParamsSuccessMethod = "Success"
+	ControllerContextName  = "ControllerContext" //类型化接口第一个参数
+	WsConnName             = "WsConn"            //websocket连接参数
+	ResultStructName       = "Result"            //统一返回体
 )
 
 var APIMethods = []string{
@@ -150,9 +282,11 @@ var APIMethods = []string{
 	http.MethodOptions,
 	http.MethodTrace}
 
+var versionReg = regexp.MustCompile(`^v[0-9]+$`)
+
 var AuthType = []string{AuthTypeJWT, AuthTypeOAuth2, AuthTypeSession, AuthTypeCookie, AuthTypeSecret}
 
-var ResponseType = []string{ResponseTypeJSON, ResponseTypeXML, ResponseTypeBytes}
+var ResponseType = []string{ResponseTypeJSON, ResponseTypeXML, ResponseTypeBytes, ResponseTypeSSE, ResponseTypeStream}
 
 func (a *Api) AnalysisAnnotate() {
 	annotates := strings.Split(a.Annotate, AnnotateSplitChar)
@@ -165,6 +299,10 @@ annotate:
 				continue annotate
 			}
 		}
+		if annotate == MethodAny {
+			a.HttpMethod = MethodAny
+			continue
+		}
 		if a.HttpMethod == annotate {
 			a.HttpMethod = strings.ToLower(a.HttpMethod)
 			continue
@@ -173,6 +311,53 @@ annotate:
 			a.Auth += strings.ReplaceAll(annotate, AuthStart, "") + " "
 			continue
 		}
+		if a.analysisPermission(annotate) {
+			continue
+		}
+		if strings.Index(annotate, MiddlewareStart) == 0 { //中间件 mw:audit,limit
+			for _, mw := range strings.Split(strings.TrimPrefix(annotate, MiddlewareStart), ",") {
+				if mw = strings.TrimSpace(mw); mw != "" {
+					a.Middlewares = append(a.Middlewares, mw)
+				}
+			}
+			continue
+		}
+		if strings.Index(annotate, TagStart) == 0 { //标签 tag:order,admin
+			for _, tag := range strings.Split(strings.TrimPrefix(annotate, TagStart), ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					a.Tags = append(a.Tags, tag)
+				}
+			}
+			continue
+		}
+		if strings.Index(annotate, OpIdStart) == 0 {
+			a.OperationId = strings.TrimSpace(strings.TrimPrefix(annotate, OpIdStart))
+			continue
+		}
+		if strings.Index(annotate, SinceStart) == 0 {
+			a.Since = strings.TrimSpace(strings.TrimPrefix(annotate, SinceStart))
+			continue
+		}
+		if versionReg.MatchString(annotate) { //版本 @GET|v2
+			a.Since = annotate
+			continue
+		}
+		if strings.Index(annotate, SummaryStart) == 0 {
+			a.Summary = strings.TrimSpace(strings.TrimPrefix(annotate, SummaryStart))
+			continue
+		}
+		if annotate == DeprecatedMark {
+			a.Deprecated = true
+			continue
+		}
+		if annotate == HiddenMark {
+			a.Hidden = true
+			continue
+		}
+		if annotate == WebSocketMark {
+			a.WebSocket = true
+			continue
+		}
 		for _, s := range AuthType {
 			if len(annotate) >= len(s) && s == annotate[:len(s)] {
 				a.Auth = s
@@ -185,6 +370,13 @@ annotate:
 				continue annotate
 			}
 		}
+		for _, s := range []string{ResponseTypeBytes, ResponseTypeStream} { //二进制返回指定内容类型 bytes:image/png stream:text/csv
+			if strings.Index(annotate, s+":") == 0 {
+				a.ResponseType = s
+				a.ContentType = strings.TrimSpace(strings.TrimPrefix(annotate, s+":"))
+				continue annotate
+			}
+		}
 		for _, s := range ResponseType {
 			if s == annotate {
 				a.ResponseType = s
@@ -199,6 +391,87 @@ annotate:
 		}
 		a.RequestPath = annotate
 	}
+	if a.WebSocket && a.HttpMethod == "" { //@ws 升级请求为GET
+		a.HttpMethod = http.MethodGet
+	}
+}
+
+// FindTypedApi 类型化接口 func(ctx ctl.ControllerContext, req *Req) (Rsp, error) 直接从签名读取参数及返回类型
+func (a *Api) FindTypedApi(ft *ast.FuncType) bool {
+	if ft.Params == nil || len(ft.Params.List) == 0 || ft.Results == nil || len(ft.Results.List) != 2 {
+		return false
+	}
+	ctx := a.sct.FieldFromAstField(ft.Params.List[0])
+	if ctx.Type != ControllerContextName || GetExprInfo(ft.Results.List[1].Type).Type != "error" {
+		return false
+	}
+	params := ft.Params.List[1:]
+	if len(ft.Params.List[0].Names) > 1 { //func(ctx ctl.ControllerContext, ...)
+		panic("typed api " + a.Name + " parameter error, use func(ctx ctl.ControllerContext, req *Req) (Rsp, error)")
+	}
+	if len(params) > 1 || (len(params) == 1 && len(params[0].Names) > 1) {
+		panic("typed api " + a.Name + " only supports one request parameter")
+	}
+	a.Typed = true
+	response := a.sct.Pkg.pkgs.FindStruct(ctx.PkgPath, ctx.Pkg, ResultStructName).Copy()
+	a.Response = &response
+	if len(params) == 0 {
+		a.NoParams = true
+	} else {
+		if _, ok := params[0].Type.(*ast.StarExpr); !ok {
+			panic("parameter error, please check if the parameter is a pointer:" + printAst(params[0].Type))
+		}
+		a.Params, _ = a.TypeStruct(params[0].Type)
+	}
+	rsp := ft.Results.List[0].Type
+	if s, array := a.TypeStruct(rsp); s != nil {
+		a.SetResponseDataStruct(array, s)
+	} else if f := a.sct.FieldFromAstField(&ast.Field{Type: rsp}); baseTypes.CheckIn(f.Type) {
+		a.SetResponseDataType(f.Array, f.Type)
+	}
+	return true
+}
+
+// FindWebSocketApi websocket接口 func(ctx ctl.ControllerContext, conn ctl.WsConn[, req *Req]) error req为握手参数
+func (a *Api) FindWebSocketApi(ft *ast.FuncType) {
+	var params []*ast.Field
+	for _, p := range ft.Params.List { //ctx, conn ctl.X 合并声明时展开
+		for range max(len(p.Names), 1) {
+			params = append(params, p)
+		}
+	}
+	if len(params) < 2 || len(params) > 3 || ft.Results == nil || len(ft.Results.List) != 1 ||
+		a.sct.FieldFromAstField(params[0]).Type != ControllerContextName || a.sct.FieldFromAstField(params[1]).Type != WsConnName ||
+		GetExprInfo(ft.Results.List[0].Type).Type != "error" {
+		panic("websocket api " + a.Name + " parameter error, use func(ctx ctl.ControllerContext, conn ctl.WsConn[, req *Req]) error")
+	}
+	a.NoParams = len(params) == 2
+	if !a.NoParams {
+		if _, ok := params[2].Type.(*ast.StarExpr); !ok {
+			panic("parameter error, please check if the parameter is a pointer:" + printAst(params[2].Type))
+		}
+		a.Params, _ = a.TypeStruct(params[2].Type)
+	}
+}
+
+// TypeStruct 通过类型表达式查找结构体 *CreateReq []User user.Info Page[User]
+func (a *Api) TypeStruct(expr ast.Expr) (*Struct, bool) {
+	if gs, array := a.GenericStruct(expr); gs != nil {
+		return gs, array
+	}
+	f := a.sct.FieldFromAstField(&ast.Field{Type: expr})
+	if baseTypes.CheckIn(f.Type) {
+		return nil, f.Array
+	}
+	if f.PkgPath == "" {
+		f.Pkg, f.PkgPath = a.sct.Pkg.Name, a.sct.Pkg.Path
+	}
+	s := a.sct.Pkg.pkgs.FindStructPtr(f.PkgPath, f.Pkg, f.Type)
+	if s == nil {
+		return nil, f.Array
+	}
+	cp := s.Copy()
+	return &cp, f.Array
 }
 
 // FindApiParameter 构建API基础参数
@@ -223,7 +496,7 @@ func (a *Api) FindApiParameter(body *ast.BlockStmt) {
 				if call, ok := rtn.Results[0].(*ast.CallExpr); ok {
 					if method, ok := call.Fun.(*ast.SelectorExpr); ok {
 						if id, ok := method.X.(*ast.Ident); ok {
-							if id.Name == a.Point && method.Sel.Name == ApiParamsSuccessMethod {
+							if id.Name == a.Point && method.Sel.Name == ApiParamsSuccessMethod && !a.IsStream() {
 								a.GetResponseStruct(call.Args[0])
 							}
 						}
@@ -289,6 +562,11 @@ func (a *Api) GetParameterStruct(expr ast.Expr) *Struct {
 				switch st := spec.Type.(type) {
 				case *ast.StructType: //var params struct {}
 					structType = st
+				case *ast.IndexExpr, *ast.IndexListExpr: //var params Params[T]
+					if gs, _ := a.GenericStruct(st); gs != nil {
+						s := gs.Copy()
+						return &s
+					}
 				case *ast.Ident: //var params Params
 					if st.Obj != nil {
 						structType = st.Obj.Decl.(*ast.TypeSpec).Type.(*ast.StructType)
@@ -373,6 +651,10 @@ func (a *Api) GetResponseStruct(expr ast.Expr) {
 							}
 						}
 					}
+				} else if gs, array := a.GenericStruct(vs.Type); gs != nil { //泛型 var rsp Page[User]
+					a.SetResponseDataStruct(array, gs)
+				} else if is, array := a.ImportStruct(vs.Type); is != nil { //其它包或模块 var rsp user.Info
+					a.SetResponseDataStruct(array, is)
 				} else if at, ok := vs.Type.(*ast.ArrayType); ok {
 					if idt, ok := at.Elt.(*ast.Ident); ok {
 						if baseTypes.CheckIn(idt.Name) { //基础类型
@@ -413,6 +695,34 @@ func (a *Api) GetResponseStruct(expr ast.Expr) {
 	}
 }
 
+// GenericStruct 泛型结构体实例化 Page[User] []Page[User]
+func (a *Api) GenericStruct(expr ast.Expr) (*Struct, bool) {
+	f := a.sct.FieldFromAstField(&ast.Field{Type: expr})
+	if len(f.TypeArgs) == 0 {
+		return nil, false
+	}
+	if f.Pkg == "" {
+		f.Pkg = a.sct.Pkg.Name
+		f.PkgPath = a.sct.Pkg.Path
+	}
+	pkgs := a.sct.Pkg.pkgs
+	return pkgs.Instantiate(pkgs.FindStructPtr(f.PkgPath, f.Pkg, f.Type), f.TypeArgs), f.Array
+}
+
+// ImportStruct 其它包或模块中定义的结构体
+func (a *Api) ImportStruct(expr ast.Expr) (*Struct, bool) {
+	f := a.sct.FieldFromAstField(&ast.Field{Type: expr})
+	if f.PkgPath == "" || f.PkgPath == a.sct.Pkg.Path || baseTypes.CheckIn(f.Type) {
+		return nil, false
+	}
+	s := a.sct.Pkg.pkgs.FindStructPtr(f.PkgPath, f.Pkg, f.Type)
+	if s == nil {
+		return nil, false
+	}
+	is := s.Copy()
+	return &is, f.Array
+}
+
 func (a *Api) getParameterStruct(expr ast.Expr) *Struct {
 	var structType *ast.StructType
 	var structName string
@@ -616,6 +926,16 @@ func GetExprInfo(expr ast.Expr) Field {
 		f := GetExprInfo(exp.X)
 		f.Ptr = true
 		return f
+	case *ast.IndexExpr: //泛型 Page[T]
+		f := GetExprInfo(exp.X)
+		f.TypeArgs = []Field{GetExprInfo(exp.Index)}
+		return f
+	case *ast.IndexListExpr: //泛型 Map[K, V]
+		f := GetExprInfo(exp.X)
+		for _, index := range exp.Indices {
+			f.TypeArgs = append(f.TypeArgs, GetExprInfo(index))
+		}
+		return f
 	case *ast.ArrayType:
 		f := GetExprInfo(exp.Elt)
 		f.Array = true
@@ -675,8 +995,15 @@ func (a *Api) FillRequestParams(method *Method) {
 	var jsonFields []Field
 	var xmlFields []Field
 	var yamlFields []Field
+	var fileFields []Field
+	var formFields []Field  //上传文件时表单参数放入multipart
 	var combination []Field //组合参数
-	for _, f := range a.Params.Fields {
+	fields := requestFields(a.Params.Fields)
+	hasFile := false
+	for _, f := range fields {
+		hasFile = hasFile || f.In == TagParamFile
+	}
+	for _, f := range fields {
 		switch f.In {
 		case TagParamJson:
 			jsonFields = append(jsonFields, f)
@@ -684,17 +1011,34 @@ func (a *Api) FillRequestParams(method *Method) {
 			xmlFields = append(xmlFields, f)
 		case TagParamYaml:
 			yamlFields = append(yamlFields, f)
+		case TagParamFile:
+			fileFields = append(fileFields, f)
 		default:
 			if f.Name == "" {
 				combination = append(combination, f)
+			} else if f.In == TagParamFrom && hasFile {
+				formFields = append(formFields, f)
 			} else {
 				method.Parameters = append(method.Parameters, f.ToParameter())
 			}
 		}
 	}
-	if (len(jsonFields) > 0 && len(xmlFields) > 0) || (len(jsonFields) > 0 && len(yamlFields) > 0) || (len(yamlFields) > 0 && len(xmlFields) > 0) {
+	if (len(jsonFields) > 0 && len(xmlFields) > 0) || (len(jsonFields) > 0 && len(yamlFields) > 0) || (len(yamlFields) > 0 && len(xmlFields) > 0) ||
+		(len(fileFields) > 0 && len(jsonFields)+len(xmlFields)+len(yamlFields) > 0) {
 		panic("generator documentation error for api " + method.OperationId + " to many request types")
 	}
+	if len(fileFields) > 0 {
+		properties := make(map[string]Property)
+		for _, field := range append(formFields, fileFields...) {
+			for _, property := range field.ToProperty(0, 5) {
+				properties[property.Name] = property
+			}
+		}
+		prop := Property{Type: PropertyTypeObject, Properties: properties}
+		prop.FillRequired()
+		method.RequestBody = RequestBody{Content: map[string]Content{"multipart/form-data": {Schema: prop}}}
+		return
+	}
 	if len(jsonFields) > 0 {
 		properties := make(map[string]Property)
 		for _, field := range jsonFields {
@@ -713,14 +1057,65 @@ func (a *Api) FillRequestParams(method *Method) {
 	}
 }
 
+// requestFields 展开组合参数 form、uri字段作为请求参数 其余字段仍作为组合参数 如嵌入ctl.Paging
+func requestFields(fields []Field) []Field {
+	var res []Field
+	for _, f := range fields {
+		if f.Name != "" || f.Struct == nil {
+			res = append(res, f)
+			continue
+		}
+		rest := f.Struct.Copy()
+		rest.Fields = nil //仅保留未展开的字段
+		for _, sf := range requestFields(f.Struct.Fields) {
+			if sf.In == TagParamFrom || sf.In == TagParamPath {
+				res = append(res, sf)
+			} else {
+				rest.Fields = append(rest.Fields, sf)
+			}
+		}
+		if len(rest.Fields) > 0 {
+			f.Struct = &rest
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
 // FillResponse 填充返回参数
 func (a *Api) FillResponse(method *Method) {
+	if a.WebSocket {
+		method.WebSocket = &WebSocketExt{Framing: ResponseTypeJSON}
+		method.Responses = map[string]Response{"101": {Description: "Switching Protocols"}}
+		return
+	}
+	switch a.ResponseType { //流式返回
+	case ResponseTypeSSE:
+		method.Responses = map[string]Response{"200": {Description: "Server-Sent Events", Content: map[string]Content{"text/event-stream": {Schema: Property{Type: OpenApiTypeString}}}}}
+		return
+	case ResponseTypeStream:
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		method.Responses = map[string]Response{
+			"200": {Description: "stream", Content: map[string]Content{contentType: {Schema: Property{Type: OpenApiTypeString, Format: OpenApiFormatBinary}}}},
+			"206": {Description: "partial content", Content: map[string]Content{contentType: {Schema: Property{Type: OpenApiTypeString, Format: OpenApiFormatBinary}}}},
+		}
+		return
+	}
 	if a.Response == nil {
 		return
 	}
 	if a.Response != nil {
 		method.Responses = make(map[string]Response)
-		if baseTypes.CheckIn(a.Response.Name) { //非结构体时
+		if a.IsBytes() { //二进制返回
+			contentType := a.ContentType
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			method.Responses["200"] = Response{Description: a.Response.Des, Content: map[string]Content{contentType: {Schema: Property{Type: OpenApiTypeString, Format: OpenApiFormatBinary}}}}
+		} else if baseTypes.CheckIn(a.Response.Name) { //非结构体时
 			method.Responses["200"] = Response{Description: a.Response.Des, Content: map[string]Content{"text/plain": {Schema: Property{Type: (&Field{Type: a.Response.Name}).GetOpenApiType()}}}}
 		} else {
 			pp := a.Response.ToProperty()
@@ -732,6 +1127,9 @@ func (a *Api) FillResponse(method *Method) {
 					if !baseTypes.CheckIn(data.Name) {
 						name = data.Name
 					}
+					if data.Origin != "" { //泛型实例化 单独定义PageOfUser
+						a.fillGenericSchema(method, data, &pp)
+					}
 				}
 				if name == "" {
 					name = a.Group + "." + a.Name + "Rsp"
@@ -742,19 +1140,136 @@ func (a *Api) FillResponse(method *Method) {
 				method.api.Components.Schemas[name] = pp
 
 				p := Property{Ref: schemaName}
-				method.Responses["200"] = Response{Description: a.Response.Name, Content: map[string]Content{"application/json": {Schema: p}}}
+				contentType := "application/json"
+				if a.ResponseType == ResponseTypeXML {
+					contentType = "application/xml"
+				}
+				method.Responses["200"] = Response{Description: a.Response.Name, Content: map[string]Content{contentType: {Schema: p}}}
 			}
 		}
 
 	}
 }
 
-// FillSecurity 填充Security
+// fillGenericSchema 泛型实例化结构体定义到components/schemas 返回数据引用该定义
+func (a *Api) fillGenericSchema(method *Method, data *Struct, pp *Property) {
+	for _, field := range a.Response.Fields {
+		if field.Name != "Data" {
+			continue
+		}
+		dp, ok := pp.Properties[field.ParamName]
+		if !ok {
+			return
+		}
+		schema := data.ToProperty()
+		schema.Description = data.Des
+		method.api.Components.Schemas[data.Name] = schema
+		ref := Property{Ref: method.api.Components.GetSchemasName() + data.Name}
+		if dp.Type == PropertyTypeArray {
+			dp.Items = &ref
+		} else {
+			dp = Property{Name: dp.Name, Ref: ref.Ref}
+		}
+		pp.Properties[field.ParamName] = dp
+		return
+	}
+}
+
+// FillSecurity 填充Security 并按鉴权配置生成securitySchemes
 func (a *Api) FillSecurity(method *Method) {
-	if a.Auth == "" {
+	if !a.HasAuthType() {
 		return
 	}
-	if a.Auth == AuthTypeJWT {
-		method.Security = []map[string][]string{{a.AuthTo: []string{}}}
+	name := a.GetSecurityName()
+	method.Security = []map[string][]string{{name: []string{}}}
+	if method.api != nil {
+		method.api.Components.SetSecurityScheme(name, a.GetSecurityScheme())
+	}
+}
+
+// HasAuthType 是否使用了已知的鉴权方式
+func (a *Api) HasAuthType() bool {
+	for _, s := range AuthType {
+		if a.Auth == s {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSecurityName securitySchemes名称 使用鉴权所属
+func (a *Api) GetSecurityName() string {
+	if a.AuthTo != "" {
+		return a.AuthTo
+	}
+	return a.Auth
+}
+
+// GetTokenConfig 查找接口对应的鉴权配置 匹配类型及鉴权所属或配置名
+func (a *Api) GetTokenConfig() (TokenConfig, bool) {
+	tokens := a.webConfig().Token
+	for _, key := range []string{a.Auth + ":" + a.GetSecurityName(), a.GetSecurityName()} {
+		if tk, ok := tokens[key]; ok {
+			return tk, true
+		}
+	}
+	for _, tk := range tokens {
+		if tk.Typ == a.Auth && (tk.AuthTo == a.GetSecurityName() || (tk.AuthTo == "" && a.GetSecurityName() == a.Auth)) {
+			return tk, true
+		}
+	}
+	return TokenConfig{}, false
+}
+
+// GetSecurityScheme 通过鉴权配置生成securityScheme
+// JWT为http bearer(自定义header时为apiKey) OAuth2为oauth2 其余为header或cookie中的apiKey
+func (a *Api) GetSecurityScheme() SecurityScheme {
+	tk, _ := a.GetTokenConfig()
+	scheme := SecurityScheme{Description: a.Auth}
+	if a.GetSecurityName() != a.Auth {
+		scheme.Description += ":" + a.GetSecurityName()
+	}
+	switch a.Auth {
+	case AuthTypeJWT:
+		if tk.HeaderName != "" && !strings.EqualFold(tk.HeaderName, DefaultTokenHeader) { //非Authorization头只能以apiKey描述
+			scheme.Type = SecurityTypeApiKey
+			scheme.In = OpenApiInHeader
+			scheme.Name = tk.HeaderName
+			break
+		}
+		scheme.Type = SecurityTypeHttp
+		scheme.Scheme = "bearer"
+		if tk.HeaderType != "" {
+			scheme.Scheme = strings.ToLower(tk.HeaderType)
+		}
+		scheme.BearerFormat = AuthTypeJWT
+	case AuthTypeOAuth2:
+		scheme.Type = SecurityTypeOAuth2
+		scheme.Flows = make(map[string]Flow)
+		if tk.AuthorizationUrl != "" {
+			scheme.Flows["authorizationCode"] = Flow{AuthorizationUrl: tk.AuthorizationUrl, TokenUrl: tk.TokenUrl, Scopes: map[string]string{}}
+		} else {
+			tokenUrl := tk.TokenUrl
+			if tokenUrl == "" {
+				tokenUrl = DefaultOAuth2TokenUrl
+			}
+			scheme.Flows["clientCredentials"] = Flow{TokenUrl: tokenUrl, Scopes: map[string]string{}}
+		}
+	default:
+		scheme.Type = SecurityTypeApiKey
+		if tk.CookieName != "" || (a.Auth == AuthTypeCookie && tk.HeaderName == "") {
+			scheme.In = OpenApiInCookie
+			scheme.Name = tk.CookieName
+			if scheme.Name == "" {
+				scheme.Name = DefaultTokenCookie
+			}
+		} else {
+			scheme.In = OpenApiInHeader
+			scheme.Name = tk.HeaderName
+			if scheme.Name == "" {
+				scheme.Name = DefaultTokenHeader
+			}
+		}
 	}
+	return scheme
 }
@@ -48,6 +48,20 @@ var DefaultInfo = OpenAPI{
 	Paths: make(map[string]map[string]Method),
 }
 
+// NewOpenAPI 以DefaultInfo为模板创建文档 每次使用新的map 避免多次生成互相污染
+func NewOpenAPI() OpenAPI {
+	api := DefaultInfo
+	api.Components = Components{
+		Schemas:         make(map[string]Property),
+		Responses:       make(map[string]Response),
+		Headers:         make(map[string]Header),
+		RequestBodies:   make(map[string]RequestBody),
+		SecuritySchemes: make(map[string]*SecurityScheme),
+	}
+	api.Paths = make(map[string]map[string]Method)
+	return api
+}
+
 type Server struct {
 	Url         string `json:"url,omitempty"`
 	Description string `json:"description,omitempty"`
@@ -73,9 +87,16 @@ type Method struct {
 	RequestBody RequestBody           `json:"requestBody,omitempty"`
 	Responses   map[string]Response   `json:"responses,omitempty"`
 	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	WebSocket   *WebSocketExt         `json:"x-websocket,omitempty"`
 	api         *OpenAPI
 }
 
+// WebSocketExt websocket接口扩展说明 GET请求升级连接
+type WebSocketExt struct {
+	Framing string `json:"framing"` //消息格式
+}
+
 type Components struct {
 	Schemas map[string]Property `json:"schemas,omitempty"`
 
@@ -124,32 +145,38 @@ func (c *Components) SetSecurityScheme(name string, scheme SecurityScheme) {
 }
 
 type SecurityScheme struct {
-	Type         string            `json:"type,omitempty"`
-	Scheme       string            `json:"scheme,omitempty"`
-	BearerFormat string            `json:"bearerFormat,omitempty"`
-	Description  string            `json:"description,omitempty"`
-	In           string            `json:"in,omitempty"`
-	Flows        []map[string]Flow `json:"flows,omitempty"`
+	Type         string          `json:"type,omitempty"`
+	Scheme       string          `json:"scheme,omitempty"`
+	BearerFormat string          `json:"bearerFormat,omitempty"`
+	Description  string          `json:"description,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	In           string          `json:"in,omitempty"`
+	Flows        map[string]Flow `json:"flows,omitempty"`
 }
 
 type Flow struct {
 	AuthorizationUrl string            `json:"authorizationUrl,omitempty"`
 	TokenUrl         string            `json:"tokenUrl,omitempty"`
-	Scope            map[string]string `json:"scope,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
 }
 
 type Property struct {
-	Name        string `json:"-"`
-	Type        string `json:"type,omitempty"`
-	Description string `json:"description,omitempty"`
-	Format      string `json:"format,omitempty"`
-	Ref         string `json:"$ref,omitempty"`
-	isRequired  bool
-	Required    []string            `json:"required,omitempty"`
-	Items       *Property           `json:"items,omitempty"` //数组
-	File        *Property           `json:"file,omitempty"`
-	Properties  map[string]Property `json:"properties,omitempty"`
-	Enum        []string            `json:"enum,omitempty"`
+	Name                 string `json:"-"`
+	Type                 string `json:"type,omitempty"`
+	Description          string `json:"description,omitempty"`
+	Format               string `json:"format,omitempty"`
+	Ref                  string `json:"$ref,omitempty"`
+	isRequired           bool
+	Required             []string            `json:"required,omitempty"`
+	Items                *Property           `json:"items,omitempty"` //数组
+	File                 *Property           `json:"file,omitempty"`
+	Properties           map[string]Property `json:"properties,omitempty"`
+	AdditionalProperties *Property           `json:"additionalProperties,omitempty"` //map值类型
+	Enum                 []any               `json:"enum,omitempty"`
+	Example              any                 `json:"example,omitempty"` //示例值
+
+	EnumVarNames     []string `json:"x-enum-varnames,omitempty"`     //枚举常量名
+	EnumDescriptions []string `json:"x-enum-descriptions,omitempty"` //枚举标签
 }
 
 // FillRequired 遍历找出所有必传字段
@@ -178,6 +205,7 @@ type Parameter struct {
 	Deprecated      bool     `json:"deprecated,omitempty"`
 	Schema          Property `json:"schema,omitempty"`
 	Style           string   `json:"style,omitempty"`
+	Example         any      `json:"example,omitempty"`
 }
 
 type RequestBody struct {
@@ -188,11 +216,13 @@ type RequestBody struct {
 }
 
 type Content struct {
-	Type   string   `json:"type,omitempty"`
-	Schema Property `json:"schema,omitempty"`
+	Type    string   `json:"type,omitempty"`
+	Schema  Property `json:"schema,omitempty"`
+	Example any      `json:"example,omitempty"` //整个请求或返回的示例
 }
 
 type Response struct {
+	Ref         string             `json:"$ref,omitempty"`
 	Description string             `json:"description,omitempty"`
 	Content     map[string]Content `json:"content,omitempty"`
 	Headers     map[string]Header  `json:"headers,omitempty"`
@@ -2,7 +2,21 @@ package openapi
 
 // Config 配置参数
 type Config struct {
-	Web map[string]struct {
-		Prefix string `yaml:"prefix"`
-	} `yaml:"web"`
+	Web map[string]WebConfig `yaml:"web"`
+}
+
+type WebConfig struct {
+	Prefix string                 `yaml:"prefix"`
+	Token  map[string]TokenConfig `yaml:"token"`
+}
+
+// TokenConfig 鉴权配置 与config.Token一致 用于生成securitySchemes
+type TokenConfig struct {
+	HeaderName       string `yaml:"headerName"` // header名 Authorization
+	HeaderType       string `yaml:"headerType"` // 验证方式 Bearer
+	CookieName       string `yaml:"cookieName"`
+	Typ              string `yaml:"type"`   //鉴权类型
+	AuthTo           string `yaml:"authTo"` //鉴权所属
+	TokenUrl         string `yaml:"tokenUrl"`
+	AuthorizationUrl string `yaml:"authorizationUrl"`
 }
@@ -0,0 +1,322 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// diffDeep 比较属性时的最大递归深度
+const diffDeep = 10
+
+// Change 接口变更
+type Change struct {
+	Path     string `json:"path"`     //接口路径
+	Method   string `json:"method"`   //http方法
+	Breaking bool   `json:"breaking"` //是否为破坏性变更
+	Message  string `json:"message"`  //变更说明
+}
+
+func (c Change) String() string {
+	level := "[compatible]"
+	if c.Breaking {
+		level = "[BREAKING]"
+	}
+	if c.Method == "" {
+		return level + " " + c.Path + ": " + c.Message
+	}
+	return level + " " + strings.ToUpper(c.Method) + " " + c.Path + ": " + c.Message
+}
+
+type Changes []Change
+
+// HasBreaking 是否存在破坏性变更
+func (cs Changes) HasBreaking() bool {
+	for _, c := range cs {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Report 变更报告 破坏性变更在前
+func (cs Changes) Report() string {
+	if len(cs) == 0 {
+		return "no changes"
+	}
+	var breaking int
+	var sb strings.Builder
+	for _, c := range cs {
+		if c.Breaking {
+			breaking++
+		}
+		sb.WriteString(c.String() + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("%d changes, %d breaking", len(cs), breaking))
+	return sb.String()
+}
+
+// DiffFile 比较两个文档文件
+func DiffFile(oldFile, newFile string) (Changes, error) {
+	older, err := ReadOpenAPI(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := ReadOpenAPI(newFile)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(older, newer), nil
+}
+
+// ReadOpenAPI 读取生成的文档
+func ReadOpenAPI(file string) (OpenAPI, error) {
+	var api OpenAPI
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return api, err
+	}
+	err = json.Unmarshal(b, &api)
+	return api, err
+}
+
+// Diff 比较两个版本的文档
+// 删除接口或方法、新增必传参数、类型变更、删除返回字段、鉴权变更为破坏性变更
+func Diff(older, newer OpenAPI) Changes {
+	d := differ{older: older, newer: newer}
+	for _, path := range sortedKeys(older.Paths) {
+		methods, ok := newer.Paths[path]
+		if !ok {
+			d.add(path, "", true, "path removed")
+			continue
+		}
+		for _, name := range sortedKeys(older.Paths[path]) {
+			method, ok := methods[name]
+			if !ok {
+				d.add(path, name, true, "method removed")
+				continue
+			}
+			d.path, d.method = path, name
+			d.diffMethod(older.Paths[path][name], method)
+		}
+		for _, name := range sortedKeys(methods) {
+			if _, ok := older.Paths[path][name]; !ok {
+				d.add(path, name, false, "method added")
+			}
+		}
+	}
+	for _, path := range sortedKeys(newer.Paths) {
+		if _, ok := older.Paths[path]; !ok {
+			d.add(path, "", false, "path added")
+		}
+	}
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Breaking && !d.changes[j].Breaking
+	})
+	return d.changes
+}
+
+type differ struct {
+	older   OpenAPI
+	newer   OpenAPI
+	path    string
+	method  string
+	changes Changes
+}
+
+func (d *differ) add(path, method string, breaking bool, msg string) {
+	d.changes = append(d.changes, Change{Path: path, Method: method, Breaking: breaking, Message: msg})
+}
+
+func (d *differ) change(breaking bool, format string, args ...any) {
+	d.add(d.path, d.method, breaking, fmt.Sprintf(format, args...))
+}
+
+func (d *differ) diffMethod(older, newer Method) {
+	d.diffParameters(older.Parameters, newer.Parameters)
+	d.diffRequestBody(older.RequestBody, newer.RequestBody)
+	d.diffResponses(older.Responses, newer.Responses)
+	d.diffSecurity(older.Security, newer.Security)
+	if newer.Deprecated && !older.Deprecated {
+		d.change(false, "deprecated")
+	}
+}
+
+func (d *differ) diffParameters(older, newer []Parameter) {
+	key := func(p Parameter) string { return p.In + ":" + p.Name }
+	olds := make(map[string]Parameter)
+	for _, p := range older {
+		olds[key(p)] = p
+	}
+	news := make(map[string]Parameter)
+	for _, p := range newer {
+		news[key(p)] = p
+		o, ok := olds[key(p)]
+		if !ok {
+			if p.Required {
+				d.change(true, "required %s parameter %s added", p.In, p.Name)
+			} else {
+				d.change(false, "%s parameter %s added", p.In, p.Name)
+			}
+			continue
+		}
+		if p.Required && !o.Required {
+			d.change(true, "%s parameter %s became required", p.In, p.Name)
+		}
+		d.diffProperty(p.In+" parameter "+p.Name, o.Schema, p.Schema, true, 0)
+	}
+	for _, p := range older {
+		if _, ok := news[key(p)]; !ok {
+			d.change(false, "%s parameter %s removed", p.In, p.Name)
+		}
+	}
+}
+
+func (d *differ) diffRequestBody(older, newer RequestBody) {
+	for _, typ := range sortedKeys(older.Content) {
+		content, ok := newer.Content[typ]
+		if !ok {
+			d.change(true, "request body %s removed", typ)
+			continue
+		}
+		d.diffProperty("request body", older.Content[typ].Schema, content.Schema, true, 0)
+	}
+	for _, typ := range sortedKeys(newer.Content) {
+		if _, ok := older.Content[typ]; !ok {
+			d.change(len(older.Content) > 0, "request body %s added", typ)
+		}
+	}
+}
+
+func (d *differ) diffResponses(older, newer map[string]Response) {
+	for _, code := range sortedKeys(older) {
+		if !strings.HasPrefix(code, "2") { //错误返回由错误码生成 不比较
+			continue
+		}
+		rsp, ok := newer[code]
+		if !ok {
+			d.change(true, "response %s removed", code)
+			continue
+		}
+		for _, typ := range sortedKeys(older[code].Content) {
+			content, ok := rsp.Content[typ]
+			if !ok {
+				d.change(true, "response %s %s removed", code, typ)
+				continue
+			}
+			d.diffProperty("response", older[code].Content[typ].Schema, content.Schema, false, 0)
+		}
+	}
+}
+
+func (d *differ) diffSecurity(older, newer []map[string][]string) {
+	names := func(list []map[string][]string) string {
+		var res []string
+		for _, m := range list {
+			res = append(res, sortedKeys(m)...)
+		}
+		sort.Strings(res)
+		return strings.Join(res, ",")
+	}
+	o, n := names(older), names(newer)
+	switch {
+	case o == n:
+	case o == "":
+		d.change(true, "authentication %s added", n)
+	case n == "":
+		d.change(false, "authentication %s removed", o)
+	default:
+		d.change(true, "authentication changed from %s to %s", o, n)
+	}
+}
+
+// diffProperty 比较属性 request为true时比较请求参数 否则比较返回
+func (d *differ) diffProperty(name string, older, newer Property, request bool, storey int) {
+	if storey > diffDeep {
+		return
+	}
+	older = d.resolve(d.older, older)
+	newer = d.resolve(d.newer, newer)
+	if older.Type != newer.Type && older.Type != "" && newer.Type != "" {
+		d.change(true, "%s type changed from %s to %s", name, older.Type, newer.Type)
+		return
+	}
+	if older.Format != newer.Format && older.Format != "" && newer.Format != "" {
+		d.change(false, "%s format changed from %s to %s", name, older.Format, newer.Format)
+	}
+	if request {
+		for _, v := range older.Enum {
+			if !containsValue(newer.Enum, v) && len(newer.Enum) > 0 {
+				d.change(true, "%s enum value %v removed", name, v)
+			}
+		}
+	}
+	if older.Items != nil && newer.Items != nil {
+		d.diffProperty(name+"[]", *older.Items, *newer.Items, request, storey+1)
+	}
+	for _, key := range sortedKeys(older.Properties) {
+		prop, ok := newer.Properties[key]
+		field := name + "." + key
+		if !ok {
+			d.change(!request, "%s field removed", field)
+			continue
+		}
+		if request && contains(newer.Required, key) && !contains(older.Required, key) {
+			d.change(true, "%s field became required", field)
+		}
+		d.diffProperty(field, older.Properties[key], prop, request, storey+1)
+	}
+	for _, key := range sortedKeys(newer.Properties) {
+		if _, ok := older.Properties[key]; ok {
+			continue
+		}
+		field := name + "." + key
+		if request && contains(newer.Required, key) {
+			d.change(true, "required %s field added", field)
+		} else {
+			d.change(false, "%s field added", field)
+		}
+	}
+}
+
+// resolve 解析components/schemas引用
+func (d *differ) resolve(api OpenAPI, p Property) Property {
+	for i := 0; p.Ref != "" && i < diffDeep; i++ {
+		s, ok := api.Components.Schemas[strings.TrimPrefix(p.Ref, api.Components.GetSchemasName())]
+		if !ok {
+			break
+		}
+		p = s
+	}
+	return p
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if fmt.Sprint(item) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
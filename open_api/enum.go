@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Enum 字典枚举类型 对应enum.GenEnum生成的类型
+type Enum struct {
+	Name   string      `json:"name"`   //类型名
+	Type   string      `json:"type"`   //基础类型
+	Des    string      `json:"des"`    //描述
+	Object bool        `json:"object"` //是否实现MarshalJSON 序列化为{"key":..,"val":..}
+	Values []EnumValue `json:"values"` //枚举值
+}
+
+type EnumValue struct {
+	Name  string `json:"name"`  //常量名
+	Value int64  `json:"value"` //值
+	Label string `json:"label"` //标签
+}
+
+const (
+	EnumMarshalMethod = "MarshalJSON"
+	EnumKeyName       = "key"
+	EnumValName       = "val"
+)
+
+// FindPkgEnum 查找包内的枚举定义 整数类型且存在常量值的类型视为枚举
+func (pkg *Package) FindPkgEnum() {
+	pkg.Enums = make(map[string]*Enum)
+	for _, file := range pkg.astPkg.Files {
+		for _, decl := range file.Decls {
+			if gendecl, ok := decl.(*ast.GenDecl); ok && gendecl.Tok == token.TYPE {
+				for _, spec := range gendecl.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						if id, ok := ts.Type.(*ast.Ident); ok && integerTypes.CheckIn(id.Name) {
+							des := FormatComment(gendecl.Doc)
+							if des == "" {
+								des = ts.Name.Name
+							}
+							pkg.Enums[ts.Name.Name] = &Enum{Name: ts.Name.Name, Type: id.Name, Des: des}
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(pkg.Enums) == 0 {
+		return
+	}
+	for _, file := range pkg.astPkg.Files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range d.Specs {
+					vs, ok := spec.(*ast.ValueSpec)
+					if !ok || vs.Type == nil || len(vs.Names) != len(vs.Values) {
+						continue
+					}
+					id, ok := vs.Type.(*ast.Ident)
+					if !ok || pkg.Enums[id.Name] == nil {
+						continue
+					}
+					for i, name := range vs.Names {
+						val, ok := enumConstValue(vs.Values[i])
+						if !ok {
+							continue
+						}
+						label := FormatComment(vs.Comment)
+						if label == "" {
+							label = FormatComment(vs.Doc)
+						}
+						em := pkg.Enums[id.Name]
+						em.Values = append(em.Values, EnumValue{Name: name.Name, Value: val, Label: label})
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) != 1 || d.Name.Name != EnumMarshalMethod {
+					continue
+				}
+				f := GetExprInfo(d.Recv.List[0].Type)
+				if em := pkg.Enums[f.Type]; em != nil {
+					em.Object = true
+				}
+			}
+		}
+	}
+	for name, em := range pkg.Enums {
+		if len(em.Values) == 0 { //没有常量值的整数类型不是枚举
+			delete(pkg.Enums, name)
+		}
+	}
+}
+
+func enumConstValue(expr ast.Expr) (int64, bool) {
+	switch exp := expr.(type) {
+	case *ast.BasicLit:
+		if exp.Kind != token.INT {
+			return 0, false
+		}
+		val, err := strconv.ParseInt(exp.Value, 0, 64)
+		return val, err == nil
+	case *ast.UnaryExpr:
+		val, ok := enumConstValue(exp.X)
+		if exp.Op == token.SUB {
+			val = -val
+		}
+		return val, ok
+	case *ast.ParenExpr:
+		return enumConstValue(exp.X)
+	}
+	return 0, false
+}
+
+// FindEnum 查找枚举定义
+func (pkgs *Packages) FindEnum(path, pkg, name string) *Enum {
+	if p := pkgs.FindPackage(path, pkg); p != nil {
+		return p.Enums[name]
+	}
+	return nil
+}
+
+// ToProperty 枚举属性 object为true且实现MarshalJSON时返回{"key":..,"val":..}对象
+func (em *Enum) ToProperty(object bool) Property {
+	var enum []any
+	var names, labels []string
+	for _, v := range em.Values {
+		enum = append(enum, v.Value)
+		names = append(names, v.Name)
+		labels = append(labels, v.Label)
+	}
+	key := Property{Type: OpenApiTypeInteger, Format: em.Type, Enum: enum, EnumVarNames: names, EnumDescriptions: labels}
+	if !object || !em.Object {
+		return key
+	}
+	var vals []any
+	for _, label := range labels {
+		vals = append(vals, label)
+	}
+	key.Name = EnumKeyName
+	val := Property{Name: EnumValName, Type: OpenApiTypeString, Enum: vals}
+	return Property{Type: OpenApiTypeObject, Properties: map[string]Property{EnumKeyName: key, EnumValName: val}}
+}
+
+// Label 枚举值对应的标签
+func (em *Enum) Label(value any) (string, bool) {
+	for _, v := range em.Values {
+		if strconv.FormatInt(v.Value, 10) == fmt.Sprint(value) {
+			return v.Label, true
+		}
+	}
+	return "", false
+}
+
+// Describe 枚举说明 1:待支付,2:已支付
+func (em *Enum) Describe() string {
+	var list []string
+	for _, v := range em.Values {
+		list = append(list, strconv.FormatInt(v.Value, 10)+":"+v.Label)
+	}
+	return strings.Join(list, ",")
+}
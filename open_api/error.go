@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	e "github.com/carlos-yuan/cargen/core/error"
+	"github.com/carlos-yuan/cargen/util/doc"
+)
+
+// ErrorResultName 错误返回结构体名称 对应e.Err
+const ErrorResultName = "ErrorResult"
+
+// errorResponseNames http状态码对应的components/responses名称
+var errorResponseNames = map[int]string{
+	http.StatusBadRequest:          "BadRequest",
+	http.StatusUnauthorized:        "Unauthorized",
+	http.StatusForbidden:           "Forbidden",
+	http.StatusInternalServerError: "InternalServerError",
+}
+
+// docCodeNames 未在codeNameMap注册 仅用于文档描述的错误码名称
+var docCodeNames = map[int]string{
+	e.ParamsValidatorErrorCode: "参数验证失败:",
+	e.ParamsDealErrorCode:      "参数处理失败:",
+	e.NotFindErrorCode:         "未找到内容:",
+	e.RPCServerErrorCode:       "服务调用错误:",
+}
+
+// errorCodeNames 已注册的错误码名称 补充文档专用名称
+func errorCodeNames() map[int]string {
+	names := e.CodeNames()
+	for code, name := range docCodeNames {
+		if _, ok := names[code]; !ok {
+			names[code] = name
+		}
+	}
+	return names
+}
+
+// FillErrorComponents 通过已注册的错误码生成ErrorResult和components/responses
+func (api *OpenAPI) FillErrorComponents() {
+	api.Components.Schemas[ErrorResultName] = errorResultProperty()
+	codes := make(map[int][]int) //map[http状态码][]错误码
+	names := errorCodeNames()
+	for code := range names {
+		status := e.HttpStatus(code)
+		codes[status] = append(codes[status], code)
+	}
+	for status, name := range errorResponseNames {
+		list := codes[status]
+		sort.Ints(list)
+		var des []string
+		for _, code := range list {
+			des = append(des, strconv.Itoa(code)+":"+strings.TrimSuffix(names[code], ":"))
+		}
+		schema := Property{Ref: api.Components.GetSchemasName() + ErrorResultName}
+		description := http.StatusText(status)
+		if len(des) > 0 {
+			description += " " + strings.Join(des, ",")
+		}
+		api.Components.Responses[name] = Response{Description: description, Content: map[string]Content{"application/json": {Schema: schema}}}
+	}
+}
+
+// errorResultProperty 通过e.Err的json标签构建错误返回体
+func errorResultProperty() Property {
+	p := Property{Type: PropertyTypeObject, Properties: make(map[string]Property)}
+	typ := reflect.TypeOf(e.Err{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := doc.GetTagName(field.Tag.Get("json"))
+		if name == "" || name == "-" {
+			continue
+		}
+		f := Field{Type: field.Type.Kind().String()}
+		p.Properties[name] = Property{Name: name, Type: f.GetOpenApiType(), Format: f.Type}
+	}
+	return p
+}
+
+// FillErrorResponse 填充错误返回
+// 400 绑定参数时 401 需要鉴权时 403 需要权限时 500 所有接口
+func (a *Api) FillErrorResponse(method *Method) {
+	if method.Responses == nil {
+		method.Responses = make(map[string]Response)
+	}
+	var status []int
+	if a.Params != nil {
+		status = append(status, http.StatusBadRequest)
+	}
+	if a.Auth != "" {
+		status = append(status, http.StatusUnauthorized)
+	}
+	if len(a.Permissions) > 0 {
+		status = append(status, http.StatusForbidden)
+	}
+	status = append(status, http.StatusInternalServerError)
+	for _, s := range status {
+		method.Responses[strconv.Itoa(s)] = Response{Ref: method.api.Components.GetResponsesName() + errorResponseNames[s]}
+	}
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// exampleDeep 生成示例时的最大递归深度
+const exampleDeep = 10
+
+// GetExample 示例值 未设置时通过属性生成 枚举取第一个值
+func (p Property) GetExample() any {
+	return p.example(nil, 0)
+}
+
+// Example 示例值 解析components/schemas中的引用
+func (c Components) Example(p Property) any {
+	return p.example(c.Schemas, 0)
+}
+
+func (p Property) example(schemas map[string]Property, storey int) any {
+	if p.Example != nil {
+		return p.Example
+	}
+	if storey > exampleDeep {
+		return nil
+	}
+	if p.Ref != "" {
+		if s, ok := schemas[strings.TrimPrefix(p.Ref, OpenApiSchemasPrefix)]; ok {
+			return s.example(schemas, storey+1)
+		}
+		return nil
+	}
+	if len(p.Properties) > 0 || p.Type == OpenApiTypeObject {
+		m := make(map[string]any)
+		for name, item := range p.Properties {
+			if item.IsBinary() { //上传文件无示例
+				continue
+			}
+			m[name] = item.example(schemas, storey+1)
+		}
+		return m
+	}
+	switch p.Type {
+	case OpenApiTypeArray:
+		if p.Items == nil {
+			return []any{}
+		}
+		return []any{p.Items.example(schemas, storey+1)}
+	case OpenApiTypeInteger, OpenApiTypeNumber:
+		if len(p.Enum) > 0 {
+			return p.Enum[0]
+		}
+		return 0
+	case OpenApiTypeBoolean:
+		return false
+	case OpenApiTypeString:
+		if len(p.Enum) > 0 {
+			return p.Enum[0]
+		}
+		if p.Format == OpenApiFormatBinary { //二进制内容无示例
+			return nil
+		}
+		return ""
+	}
+	return nil
+}
+
+// IsBinary 是否为二进制内容或文件数组
+func (p Property) IsBinary() bool {
+	return p.Format == OpenApiFormatBinary || (p.Items != nil && p.Items.Format == OpenApiFormatBinary)
+}
+
+// ExampleString 示例值字符串 用于查询及路径参数
+func (p Property) ExampleString() string {
+	switch v := p.GetExample().(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int, int64, float64, bool:
+		return fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// FillExample 生成整个请求体及返回的示例
+func (a *Api) FillExample(method *Method) {
+	for typ, content := range method.RequestBody.Content {
+		content.Example = method.api.Components.Example(content.Schema)
+		method.RequestBody.Content[typ] = content
+	}
+	rsp, ok := method.Responses["200"]
+	if !ok || a.IsStream() { //流式返回没有固定示例
+		return
+	}
+	for typ, content := range rsp.Content {
+		content.Example = method.api.Components.Example(content.Schema)
+		rsp.Content[typ] = content
+	}
+}
@@ -1,8 +1,10 @@
 package openapi
 
 import (
+	"encoding/json"
 	"github.com/carlos-yuan/cargen/util/doc"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/carlos-yuan/cargen/util/convert"
@@ -22,6 +24,9 @@ type Field struct {
 	Ptr       bool    `json:"ptr"`       //是否指针
 	MapInfo   MapInfo `json:"mapInfo"`   //map类型键值信息
 	Struct    *Struct `json:"struct"`    //是结构体时
+	Enum      *Enum   `json:"enum"`      //是字典枚举时
+	TypeArgs  []Field `json:"typeArgs"`  //泛型类型参数 Page[User]
+	Example   string  `json:"example"`   //示例值 example标签或注释中的eg:标记
 }
 
 type MapInfo struct {
@@ -42,6 +47,7 @@ const (
 	TagParamJson = "json"
 	TagParamXml  = "xml"
 	TagParamYaml = "yaml"
+	TagParamFile = "file" //multipart文件 []byte或[][]byte
 
 	OpenApiInPath   = "path"
 	OpenApiInQuery  = "query"
@@ -49,6 +55,8 @@ const (
 	OpenApiInCookie = "cookie"
 
 	TagValidate = "validate"
+	TagExample  = "example"
+	ExampleMark = "eg:" //注释中的示例标记 //名称 eg:张三
 
 	OpenApiTypeArray   = "array"
 	OpenApiTypeBoolean = "boolean"
@@ -58,10 +66,12 @@ const (
 	OpenApiTypeObject  = "object"
 	OpenApiTypeString  = "string"
 
+	OpenApiFormatBinary = "binary"
+
 	OpenApiSchemasPrefix = "#/components/schemas/"
 )
 
-var tagParams = []string{TagParamPath, TagParamFrom, TagParamJson, TagParamXml, TagParamYaml}
+var tagParams = []string{TagParamPath, TagParamFrom, TagParamJson, TagParamXml, TagParamYaml, TagParamFile}
 
 func GetTagInfo(fieldTag string) (tag, name, validate string) {
 	if fieldTag == "" {
@@ -105,20 +115,50 @@ func (bt BaseType) CheckIn(typ string) bool {
 	return false
 }
 
-// ToProperty 找到属性
+// ToProperty 找到属性 请求参数
 // deep 递归层数 max递归深度
 func (f Field) ToProperty(storey, deep int) []Property {
+	return f.toProperty(storey, deep, false)
+}
+
+// ToResponseProperty 找到属性 返回参数 字典枚举按MarshalJSON输出对象
+func (f Field) ToResponseProperty(storey, deep int) []Property {
+	return f.toProperty(storey, deep, true)
+}
+
+func (f Field) toProperty(storey, deep int, rsp bool) []Property {
 	if f.Name != "" && convert.FistIsLower(f.Name) { //小写开头的隐藏字段去掉
 		return []Property{}
 	}
+	if f.In == TagParamFile {
+		return []Property{f.fileProperty()}
+	}
 	if storey > deep { //深度限制
 		return []Property{}
 	}
 	var p []Property
-	if !baseTypes.CheckIn(f.Type) { //非一般类型
+	if f.Enum != nil { //字典枚举
+		property := f.Enum.ToProperty(rsp)
+		property.Name = f.ParamName
+		property.Description = f.ToParameter().Description
+		property.isRequired = f.IsRequired()
+		if example := f.GetExample(); example != nil {
+			if property.Type != OpenApiTypeObject {
+				property.Example = example
+			} else if label, ok := f.Enum.Label(example); ok { //{"key":..,"val":..}
+				property.Example = map[string]any{EnumKeyName: example, EnumValName: label}
+			}
+		}
+		if f.Array {
+			property = Property{Name: property.Name, Description: property.Description, isRequired: property.isRequired, Type: PropertyTypeArray, Items: &property}
+			property.Items.Name = ""
+			property.Items.Description = ""
+		}
+		p = append(p, property)
+	} else if !baseTypes.CheckIn(f.Type) { //非一般类型
 		if f.Name == "" { //组合参数T{K}
 			for _, field := range f.Struct.Fields {
-				p = append(p, field.ToProperty(storey+1, deep)...)
+				p = append(p, field.toProperty(storey+1, deep, rsp)...)
 			}
 		} else { //嵌套对象
 			property := Property{Name: f.ParamName, Description: f.ToParameter().Description, isRequired: f.IsRequired(), Properties: make(map[string]Property)}
@@ -130,7 +170,7 @@ func (f Field) ToProperty(storey, deep int) []Property {
 			if f.Struct != nil { //字段的结构体不为空时，寻找字段定义
 				var fpps []Property
 				for _, field := range f.Struct.Fields {
-					pps := field.ToProperty(storey+1, deep)
+					pps := field.toProperty(storey+1, deep, rsp)
 					if len(pps) == 0 {
 						continue
 					}
@@ -186,11 +226,11 @@ func (f Field) ToProperty(storey, deep int) []Property {
 		}
 	} else { //一般类型
 		if f.Array { //数组类型
-			pp := Property{Name: f.ParamName, Description: f.ToParameter().Description, isRequired: f.IsRequired(), Type: PropertyTypeArray, Format: f.GetType()}
+			pp := Property{Name: f.ParamName, Description: f.ToParameter().Description, isRequired: f.IsRequired(), Type: PropertyTypeArray, Format: f.GetType(), Example: f.GetExample()}
 			pp.Items = &Property{Type: f.GetOpenApiType()}
 			p = append(p, pp)
 		} else {
-			p = append(p, Property{Name: f.ParamName, Description: f.ToParameter().Description, isRequired: f.IsRequired(), Type: f.GetOpenApiType(), Format: f.GetType()})
+			p = append(p, Property{Name: f.ParamName, Description: f.ToParameter().Description, isRequired: f.IsRequired(), Type: f.GetOpenApiType(), Format: f.GetType(), Example: f.GetExample()})
 		}
 	}
 	return p
@@ -203,8 +243,17 @@ func (f Field) ToParameter() Parameter {
 		Description: f.Comment,
 		Schema:      Property{Type: f.GetOpenApiType(), Format: f.GetType()},
 	}
+	if f.Enum != nil {
+		param.Schema = f.Enum.ToProperty(false)
+		if param.Description != "" {
+			param.Description += " "
+		}
+		param.Description += f.Enum.Describe()
+	}
+	param.Example = f.GetExample()
+	param.Schema.Example = param.Example
 	if f.Validate != "" {
-		param.Required = true
+		param.Required = f.IsRequired()
 		if f.Validate != "required" {
 			param.Description += "参数验证:" + f.Validate
 		}
@@ -212,8 +261,82 @@ func (f Field) ToParameter() Parameter {
 	return param
 }
 
+// FillExample 读取示例值 example标签优先 其次为注释中的eg:标记 标记从注释中去除
+func (f *Field) FillExample() {
+	if i := strings.Index(f.Comment, ExampleMark); i >= 0 {
+		f.Example = strings.TrimSpace(f.Comment[i+len(ExampleMark):])
+		f.Comment = strings.TrimRight(strings.TrimSpace(f.Comment[:i]), ",")
+	}
+	if f.Tag != "" {
+		if val, ok := reflect.StructTag(f.Tag[1 : len(f.Tag)-1]).Lookup(TagExample); ok {
+			f.Example = val
+		}
+	}
+}
+
+// GetExample 示例值 按字段类型转换 数组可使用json或逗号分隔
+func (f Field) GetExample() any {
+	if f.Example == "" {
+		return nil
+	}
+	if f.Array {
+		var list []any
+		if json.Unmarshal([]byte(f.Example), &list) == nil {
+			return list
+		}
+		for _, item := range strings.Split(f.Example, ",") {
+			list = append(list, f.exampleValue(strings.TrimSpace(item)))
+		}
+		return list
+	}
+	return f.exampleValue(f.Example)
+}
+
+func (f Field) exampleValue(val string) any {
+	typ := f.GetOpenApiType()
+	if f.Enum != nil {
+		typ = OpenApiTypeInteger
+	}
+	switch typ {
+	case OpenApiTypeInteger:
+		if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return i
+		}
+	case OpenApiTypeNumber:
+		if n, err := strconv.ParseFloat(val, 64); err == nil {
+			return n
+		}
+	case OpenApiTypeBoolean:
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	case OpenApiTypeString:
+		return val
+	}
+	var v any
+	if json.Unmarshal([]byte(val), &v) == nil { //对象示例使用json
+		return v
+	}
+	return val
+}
+
+// fileProperty 上传文件 [][]byte为多文件
+func (f Field) fileProperty() Property {
+	p := Property{Name: f.ParamName, Description: f.Comment, isRequired: f.IsRequired(), Type: OpenApiTypeString, Format: OpenApiFormatBinary}
+	if f.Type == "[]byte" {
+		p = Property{Name: p.Name, Description: p.Description, isRequired: p.isRequired, Type: PropertyTypeArray, Items: &Property{Type: p.Type, Format: p.Format}}
+	}
+	return p
+}
+
+// IsRequired 校验规则含required时必传 如omitempty,max=11可不传
 func (f Field) IsRequired() bool {
-	return f.Validate != ""
+	for _, rule := range strings.Split(f.Validate, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
 }
 
 func (f *Field) GetOpenApiIn() string {
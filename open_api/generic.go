@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/carlos-yuan/cargen/util/convert"
+)
+
+// genericDeep 泛型实例化最大递归深度 避免Node[T]{Children []Node[T]}无限展开
+const genericDeep = 5
+
+// Instantiate 泛型结构体实例化 Page[User] 实例化后的结构体名称为PageOfUser
+// 非泛型或未传入类型参数时原样返回
+func (pkgs *Packages) Instantiate(s *Struct, args []Field) *Struct {
+	return pkgs.instantiate(s, args, 0)
+}
+
+func (pkgs *Packages) instantiate(s *Struct, args []Field, storey int) *Struct {
+	if s == nil || len(s.TypeParams) == 0 || len(args) == 0 || storey > genericDeep {
+		return s
+	}
+	params := make(map[string]Field)
+	for i, name := range s.TypeParams {
+		if i < len(args) {
+			params[name] = args[i]
+		}
+	}
+	ins := s.Copy()
+	ins.Des = s.Des
+	ins.Name = GenericName(s.Name, args)
+	ins.Origin = s.Name
+	ins.TypeArgs = args
+	ins.TypeParams = nil
+	ins.Fields = make(Fields, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		ins.Fields = append(ins.Fields, pkgs.substitute(f, params, storey))
+	}
+	return &ins
+}
+
+// substitute 替换字段中的类型参数
+func (pkgs *Packages) substitute(f Field, params map[string]Field, storey int) Field {
+	if arg, ok := params[f.Type]; ok && len(f.TypeArgs) == 0 { //字段类型为类型参数 T
+		sub := arg
+		sub.Name = f.Name
+		sub.Tag = f.Tag
+		sub.In = f.In
+		sub.ParamName = f.ParamName
+		sub.Validate = f.Validate
+		sub.Comment = f.Comment
+		sub.Example = f.Example
+		sub.Array = f.Array || arg.Array
+		sub.Ptr = f.Ptr
+		if !baseTypes.CheckIn(sub.Type) {
+			sub.Struct = pkgs.instantiate(pkgs.FindStructPtr(sub.PkgPath, sub.Pkg, sub.Type), sub.TypeArgs, storey+1)
+			sub.Enum = pkgs.FindEnum(sub.PkgPath, sub.Pkg, sub.Type)
+		}
+		return sub
+	}
+	if len(f.TypeArgs) > 0 { //字段类型为泛型 List[T]
+		args := make([]Field, 0, len(f.TypeArgs))
+		for _, arg := range f.TypeArgs {
+			args = append(args, pkgs.substitute(arg, params, storey+1))
+		}
+		f.TypeArgs = args
+		f.Struct = pkgs.instantiate(pkgs.FindStructPtr(f.PkgPath, f.Pkg, f.Type), args, storey+1)
+	}
+	return f
+}
+
+// GenericName 泛型实例化名称 Page[User]为PageOfUser Map[string,[]User]为MapOfStringAndUserList
+func GenericName(name string, args []Field) string {
+	var names []string
+	for _, arg := range args {
+		n := convert.ToCamelCase(arg.Type)
+		if len(arg.TypeArgs) > 0 {
+			n = GenericName(n, arg.TypeArgs)
+		}
+		if arg.Array {
+			n += "List"
+		}
+		names = append(names, n)
+	}
+	return name + "Of" + strings.Join(names, "And")
+}
+
+// FillPkgGenericStruct 实例化字段中的泛型结构体 需要在FillPkgRelationStruct之后执行
+func (pkgs *Packages) FillPkgGenericStruct() {
+	for i, pkg := range *pkgs {
+		for is, sct := range pkg.Structs {
+			for id, fd := range sct.Fields {
+				if len(fd.TypeArgs) > 0 {
+					(*pkgs)[i].Structs[is].Fields[id].Struct = pkgs.Instantiate(pkgs.FindStructPtr(fd.PkgPath, fd.Pkg, fd.Type), fd.TypeArgs)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,514 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/carlos-yuan/cargen/util/fileUtil"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadMode 加载包所需的信息 仅加载的包本身解析语法树 不递归解析依赖源码
+// 类型信息由go/types检查得到 依赖包的类型从编译导出数据获取 导出数据由当前工具链的标准库读取
+const LoadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedImports | packages.NeedModule
+
+// exportMode 加载依赖包的编译导出文件
+const exportMode = packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedExportFile
+
+// Loader 基于go/packages的包加载器
+// 模块并发加载 依赖包按需加载 配置按模块缓存
+type Loader struct {
+	Dirs     []string           //所有模块目录
+	Work     string             //go.work文件 为空时各模块分别加载
+	Tags     []string           //构建标签 如hertz
+	mux      sync.Mutex         //
+	loaded   map[string]bool    //已加载或尝试加载过的包路径
+	configs  map[string]*Config //map[模块目录]配置
+	building atomic.Int32       //正在构建依赖包 避免依赖包的依赖被连锁加载
+}
+
+func NewLoader(dirs ...string) *Loader {
+	return &Loader{Dirs: dirs, loaded: make(map[string]bool), configs: make(map[string]*Config)}
+}
+
+// FindLoader 查找目录下的模块创建加载器 目录位于go.work工作区内时按工作区加载
+func FindLoader(base string) (*Loader, error) {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err
+	}
+	if work := FindWorkFile(base); work != "" {
+		return NewWorkLoader(work, base)
+	}
+	dirs, err := FindModules(base)
+	if err != nil {
+		return nil, err
+	}
+	return NewLoader(dirs...), nil
+}
+
+// NewWorkLoader 通过go.work创建加载器 只加载base下的模块及包含base的模块
+func NewWorkLoader(work, base string) (*Loader, error) {
+	b, err := os.ReadFile(work)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := modfile.ParseWork(work, b, nil)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, use := range wf.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(work), dir)
+		}
+		if inDir(dir, base) || inDir(base, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	l := NewLoader(dirs...)
+	l.Work = work
+	return l, nil
+}
+
+// FindWorkFile 从目录向上查找go.work GOWORK=off时不使用工作区
+func FindWorkFile(dir string) string {
+	switch env := os.Getenv("GOWORK"); env {
+	case "off":
+		return ""
+	case "":
+	default:
+		return env
+	}
+	for {
+		file := filepath.Join(dir, "go.work")
+		if fileUtil.IsExist(file) {
+			return file
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// FindModules 查找目录下的所有模块
+func FindModules(base string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != base && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "go.mod" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// skipDir 不查找模块和配置的目录
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || name == "node_modules" || strings.HasPrefix(name, ".")
+}
+
+// inDir path是否在dir目录下
+func inDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
+// LoadModules 并发加载所有模块下的包
+// 工作区模式下模块间的引用及replace由工作区解析 加载失败的模块跳过并输出警告 全部失败时panic
+func (l *Loader) LoadModules() []*packages.Package {
+	var wg sync.WaitGroup
+	results := make([][]*packages.Package, len(l.Dirs))
+	failed := make([]any, len(l.Dirs)) //加载失败的模块 在调用方协程中处理
+	for i, dir := range l.Dirs {
+		wg.Add(1)
+		go func(i int, dir string) {
+			defer wg.Done()
+			defer func() { failed[i] = recover() }()
+			results[i] = l.load(dir, "./...")
+		}(i, dir)
+	}
+	wg.Wait()
+	var list []*packages.Package
+	var errs []string
+	for i, res := range results {
+		if failed[i] == nil {
+			list = append(list, res...)
+			continue
+		}
+		if l.Work == "" {
+			panic(failed[i])
+		}
+		errs = append(errs, fmt.Sprintf("module %s: %v", l.Dirs[i], failed[i]))
+	}
+	if len(errs) > 0 && len(errs) == len(l.Dirs) {
+		panic(fmt.Errorf("load workspace %s:\n%s", l.Work, strings.Join(errs, "\n")))
+	}
+	for _, err := range errs {
+		log.Printf("skip %s", err)
+	}
+	return list
+}
+
+// load 加载包 加载失败或包存在错误时panic 避免出错的控制器从文档和路由中遗漏
+func (l *Loader) load(dir string, patterns ...string) []*packages.Package {
+	conf := &packages.Config{Mode: LoadMode, Dir: dir, Env: l.env(), BuildFlags: l.buildFlags(), Fset: token.NewFileSet()}
+	list, err := packages.Load(conf, patterns...)
+	if err == nil {
+		err = l.check(conf, list)
+	}
+	if err != nil {
+		panic(fmt.Errorf("load packages %s: %w", dir, err))
+	}
+	var errs []string
+	var res []*packages.Package
+	for _, p := range list {
+		for _, e := range p.Errors {
+			errs = append(errs, p.PkgPath+": "+e.Error())
+		}
+		if len(p.Syntax) == 0 {
+			continue
+		}
+		res = append(res, p)
+	}
+	if len(errs) > 0 {
+		panic(fmt.Errorf("load packages %s:\n%s", dir, strings.Join(errs, "\n")))
+	}
+	l.mux.Lock()
+	for _, p := range res {
+		l.loaded[p.PkgPath] = true
+	}
+	l.mux.Unlock()
+	return res
+}
+
+// check 类型检查加载的包 填充Types及TypesInfo 类型错误记录到包的Errors
+// 加载的包按引用顺序从源码检查 相互引用时共用类型 其他依赖从编译导出数据导入
+func (l *Loader) check(conf *packages.Config, list []*packages.Package) error {
+	own := make(map[string]*packages.Package)
+	deps := make(map[string]bool)
+	for _, p := range list {
+		own[p.ID] = p
+	}
+	for _, p := range list {
+		for _, imp := range p.Imports {
+			if own[imp.ID] == nil && imp.ID != "unsafe" && imp.ID != "C" {
+				deps[imp.ID] = true
+			}
+		}
+	}
+	exports := make(map[string]string) //map[包路径]导出文件
+	if len(deps) > 0 {
+		var paths []string
+		for path := range deps {
+			paths = append(paths, path)
+		}
+		econf := *conf
+		econf.Mode = exportMode
+		depList, err := packages.Load(&econf, paths...)
+		if err != nil {
+			return err
+		}
+		packages.Visit(depList, nil, func(p *packages.Package) {
+			if p.ExportFile != "" {
+				exports[p.ID] = p.ExportFile
+			}
+		})
+	}
+	imp := importer.ForCompiler(conf.Fset, "gc", func(path string) (io.ReadCloser, error) {
+		file, ok := exports[path]
+		if !ok {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(file)
+	})
+	checked := make(map[*packages.Package]bool)
+	var checkPkg func(p *packages.Package)
+	checkPkg = func(p *packages.Package) {
+		if checked[p] {
+			return
+		}
+		checked[p] = true
+		for _, dep := range p.Imports {
+			if q := own[dep.ID]; q != nil {
+				checkPkg(q)
+			}
+		}
+		tc := &types.Config{
+			Importer: importerFunc(func(path string) (*types.Package, error) {
+				id := path
+				if dep := p.Imports[path]; dep != nil {
+					id = dep.ID
+				}
+				if id == "unsafe" {
+					return types.Unsafe, nil
+				}
+				if q := own[id]; q != nil {
+					if q.Types == nil {
+						return nil, fmt.Errorf("import cycle via %s", id)
+					}
+					return q.Types, nil
+				}
+				return imp.Import(id)
+			}),
+			Error: func(err error) {
+				if te, ok := err.(types.Error); ok {
+					p.Errors = append(p.Errors, packages.Error{Pos: te.Fset.Position(te.Pos).String(), Msg: te.Msg, Kind: packages.TypeError})
+					return
+				}
+				p.Errors = append(p.Errors, packages.Error{Msg: err.Error(), Kind: packages.TypeError})
+			},
+		}
+		if p.Module != nil && p.Module.GoVersion != "" {
+			tc.GoVersion = "go" + p.Module.GoVersion
+		}
+		p.TypesInfo = &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Implicits:  make(map[ast.Node]types.Object),
+			Instances:  make(map[*ast.Ident]types.Instance),
+			Scopes:     make(map[ast.Node]*types.Scope),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		}
+		if len(p.Errors) > 0 { //加载已出错的包不再检查 避免重复报告
+			p.Types = types.NewPackage(p.PkgPath, p.Name)
+			return
+		}
+		p.Types, _ = tc.Check(p.PkgPath, conf.Fset, p.Syntax, p.TypesInfo)
+	}
+	for _, p := range list {
+		checkPkg(p)
+	}
+	return nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// env 工作区模式下指定GOWORK 并去掉工作区不支持的-mod=mod
+func (l *Loader) env() []string {
+	if l.Work == "" {
+		return nil
+	}
+	var flags []string
+	for _, flag := range strings.Fields(os.Getenv("GOFLAGS")) {
+		if flag != "-mod=mod" {
+			flags = append(flags, flag)
+		}
+	}
+	return append(os.Environ(), "GOWORK="+l.Work, "GOFLAGS="+strings.Join(flags, " "))
+}
+
+func (l *Loader) buildFlags() []string {
+	if len(l.Tags) == 0 {
+		return nil
+	}
+	return []string{"-tags=" + strings.Join(l.Tags, ",")}
+}
+
+// root 加载依赖的目录 工作区模式下为工作区目录 模块的replace及require在该目录下生效
+func (l *Loader) root(dir string) string {
+	if l.Work != "" {
+		return filepath.Dir(l.Work)
+	}
+	if dir == "" && len(l.Dirs) > 0 {
+		return l.Dirs[0]
+	}
+	return dir
+}
+
+// NewPackage 通过加载结果构建包信息
+func (l *Loader) NewPackage(pkgs *Packages, p *packages.Package) *Package {
+	astPkg := &ast.Package{Name: p.Name, Files: make(map[string]*ast.File)}
+	for i, file := range p.Syntax {
+		name := ""
+		if i < len(p.CompiledGoFiles) {
+			name = p.CompiledGoFiles[i]
+		} else {
+			name = p.Fset.Position(file.Pos()).Filename
+		}
+		astPkg.Files[name] = file
+	}
+	pkg := &Package{Name: p.Name, Path: p.PkgPath, Structs: make(map[string]*Struct), astPkg: astPkg, pkgs: pkgs,
+		fset: p.Fset, types: p.Types, info: p.TypesInfo, loader: l}
+	if p.Module != nil {
+		pkg.ModPath = fileUtil.FixPathSeparator(p.Module.Dir)
+	} else if len(p.GoFiles) > 0 {
+		pkg.ModPath = filepath.Dir(p.GoFiles[0])
+	}
+	return pkg
+}
+
+// Preload 批量加载结构体字段引用的依赖包 依赖包在引用它的模块下加载
+func (l *Loader) Preload(pkgs *Packages) {
+	patterns := make(map[string][]string) //map[模块目录][]包路径
+	var dirs []string
+	for _, p := range *pkgs {
+		add := func(path string) {
+			if path != "" && !l.isLoaded(path) && !IsStdPackage(path) {
+				l.mux.Lock()
+				l.loaded[path] = true
+				l.mux.Unlock()
+				if patterns[p.ModPath] == nil {
+					dirs = append(dirs, p.ModPath)
+				}
+				patterns[p.ModPath] = append(patterns[p.ModPath], path)
+			}
+		}
+		for _, sct := range p.Structs {
+			for _, fd := range sct.Fields {
+				add(fd.PkgPath)
+				add(fd.MapInfo.Key.PkgPath)
+				add(fd.MapInfo.Value.PkgPath)
+			}
+		}
+	}
+	for _, dir := range dirs {
+		l.appendPackages(pkgs, l.load(l.root(dir), patterns[dir]...))
+	}
+}
+
+// LoadDependency 按需加载单个依赖包
+func (l *Loader) LoadDependency(pkgs *Packages, path string) {
+	if path == "" || l.building.Load() > 0 || l.isLoaded(path) || IsStdPackage(path) || len(l.Dirs) == 0 {
+		return
+	}
+	l.mux.Lock()
+	l.loaded[path] = true
+	l.mux.Unlock()
+	l.appendPackages(pkgs, l.load(l.root(""), path))
+}
+
+func (l *Loader) appendPackages(pkgs *Packages, list []*packages.Package) {
+	l.building.Add(1)
+	defer l.building.Add(-1)
+	var added []*Package
+	for _, p := range list {
+		pkg := l.NewPackage(pkgs, p)
+		pkg.dep = true
+		pkg.FindPkgStruct()
+		pkg.FindPkgEnum()
+		*pkgs = append(*pkgs, *pkg)
+		added = append(added, pkg)
+	}
+	for _, pkg := range added {
+		pkgs.fillRelationStruct(pkg)
+	}
+}
+
+func (l *Loader) isLoaded(path string) bool {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.loaded[path]
+}
+
+// Config 获取模块配置 每个模块只查找一次
+func (l *Loader) Config(modPath string) *Config {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if conf, ok := l.configs[modPath]; ok {
+		return conf
+	}
+	conf := FindModConfig(modPath)
+	l.configs[modPath] = conf
+	return conf
+}
+
+// FindModConfig 在模块目录下查找配置文件
+func FindModConfig(modPath string) *Config {
+	var conf *Config
+	err := filepath.Walk(modPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && path != modPath && skipDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		if info.Name() == ConfigFileName {
+			b, err := fileUtil.ReadAll(path)
+			if err != nil {
+				log.Println(err)
+			}
+			conf = &Config{}
+			err = yaml.Unmarshal(b, conf)
+			if err != nil {
+				log.Println(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		println(err.Error())
+	}
+	return conf
+}
+
+// IsStdPackage 是否为标准库
+func IsStdPackage(path string) bool {
+	if path == "C" || path == "unsafe" {
+		return true
+	}
+	if strings.Contains(strings.Split(path, "/")[0], ".") {
+		return false
+	}
+	return fileUtil.IsExist(filepath.Join(build.Default.GOROOT, "src", path))
+}
+
+// TypePkg 通过类型信息查找表达式所属的包 可以正确处理别名导入 点导入及vendor
+func (pkg *Package) TypePkg(expr ast.Expr) (name, path string) {
+	if pkg.info == nil {
+		return
+	}
+	typ := pkg.info.TypeOf(expr)
+	for typ != nil {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Named:
+			if t.Obj().Pkg() != nil {
+				return t.Obj().Pkg().Name(), t.Obj().Pkg().Path()
+			}
+			return
+		default:
+			return
+		}
+	}
+	return
+}
+
+// GetTypes 获取包的类型信息
+func (pkg *Package) GetTypes() (*types.Package, *types.Info, *token.FileSet) {
+	return pkg.types, pkg.info, pkg.fset
+}
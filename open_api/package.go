@@ -2,14 +2,11 @@ package openapi
 
 import (
 	"go/ast"
-	"log"
-	"os"
-	"path/filepath"
+	"go/token"
+	"go/types"
 	"strings"
 
 	"github.com/carlos-yuan/cargen/util/convert"
-	"github.com/carlos-yuan/cargen/util/fileUtil"
-	"gopkg.in/yaml.v3"
 )
 
 type Package struct {
@@ -17,9 +14,15 @@ type Package struct {
 	Path    string             //包路径
 	ModPath string             //模块路径
 	Structs map[string]*Struct //所有结构体信息
+	Enums   map[string]*Enum   //所有字典枚举信息
 	astPkg  *ast.Package
 	pkgs    *Packages
 	config  *Config
+	fset    *token.FileSet
+	types   *types.Package //类型信息
+	info    *types.Info
+	loader  *Loader
+	dep     bool //依赖包 仅用于查找结构体定义
 }
 
 type CreatePackageOpt struct {
@@ -31,28 +34,21 @@ func (pkg *Package) GetAstPkg() *ast.Package {
 	return pkg.astPkg
 }
 
+// IsDependency 是否为依赖包 依赖包不生成文档、路由及拷贝代码
+func (pkg *Package) IsDependency() bool {
+	return pkg.dep
+}
+
 const ConfigFileName = "config_origin.yaml"
 
 func (pkg *Package) FindConfig() {
 	if pkg.config != nil {
 		return
 	}
-	err := filepath.Walk(pkg.ModPath, func(path string, info os.FileInfo, err error) error {
-		if info.Name() == ConfigFileName {
-			b, err := fileUtil.ReadAll(path)
-			if err != nil {
-				log.Println(err)
-			}
-			pkg.config = &Config{}
-			err = yaml.Unmarshal(b, pkg.config)
-			if err != nil {
-				log.Println(err)
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		println(err.Error())
+	if pkg.loader != nil {
+		pkg.config = pkg.loader.Config(pkg.ModPath)
+	} else {
+		pkg.config = FindModConfig(pkg.ModPath)
 	}
 }
 
@@ -76,6 +72,13 @@ func (pkg *Package) FindPkgStruct() {
 						ts, ok := spec.(*ast.TypeSpec)
 						if ok {
 							s := Struct{Name: ts.Name.Name, Imports: imports, Pkg: pkg}
+							if ts.TypeParams != nil { //泛型类型参数
+								for _, param := range ts.TypeParams.List {
+									for _, name := range param.Names {
+										s.TypeParams = append(s.TypeParams, name.Name)
+									}
+								}
+							}
 							s.Des = FormatComment(gendecl.Doc)
 							if s.Des == "" {
 								s.Des = s.Name
@@ -135,10 +138,14 @@ func (pkg *Package) FindPkgApi() {
 					api.AnalysisAnnotate()
 					if api.HttpMethod != "" {
 						api.sct = pkg.Structs[api.Group]
-						f := GetExprInfo(fc.Type.Results.List[0].Type)
-						response := pkg.pkgs.FindStruct(api.sct.Imports[f.Pkg], f.Pkg, f.Type).Copy()
-						api.Response = &response
-						api.FindApiParameter(fc.Body)
+						if api.WebSocket {
+							api.FindWebSocketApi(fc.Type)
+						} else if !api.FindTypedApi(fc.Type) { //t.Bind(&params) return t.Success(rsp)
+							f := GetExprInfo(fc.Type.Results.List[0].Type)
+							response := pkg.pkgs.FindStruct(api.sct.Imports[f.Pkg], f.Pkg, f.Type).Copy()
+							api.Response = &response
+							api.FindApiParameter(fc.Body)
+						}
 						api.sct.Api = append(api.sct.Api, api)
 					}
 					if api.sct != nil { //结构体方法补充
@@ -153,3 +160,15 @@ func (pkg *Package) FindPkgApi() {
 		}
 	}
 }
+
+// Version 包版本 最后一级目录为v2等版本号时生成/v2/路由
+func (p *Package) Version() string {
+	name := p.Path
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if versionReg.MatchString(name) {
+		return name
+	}
+	return ""
+}
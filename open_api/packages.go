@@ -2,16 +2,12 @@ package openapi
 
 import (
 	"encoding/json"
-	"github.com/carlos-yuan/cargen/util/doc"
-	"io/fs"
 	"log"
-	"os"
+	"net/http"
 	"sort"
 	"strings"
-	"syscall"
 
 	"github.com/carlos-yuan/cargen/util/fileUtil"
-	"golang.org/x/mod/modfile"
 )
 
 // GenFromPath 通过目录生成
@@ -31,94 +27,58 @@ func GenFromPath(name, des, version, path, out string) {
 
 type Packages []Package
 
-func (pkgs *Packages) Init(base string) {
-	pkgs.InitPackages(base)
+// Init 加载目录下的包并查找API定义 tags为加载时使用的构建标签 如hertz
+func (pkgs *Packages) Init(base string, tags ...string) {
+	pkgs.InitPackages(base, tags...)
 	//查找API定义
 	for i := range *pkgs {
-		(*pkgs)[i].FindPkgApi()
+		if !(*pkgs)[i].IsDependency() {
+			(*pkgs)[i].FindPkgApi()
+		}
 	}
 }
 
-func (pkgs *Packages) InitPackages(base string) {
-	files, err := fileUtil.GetFilePath(base, "go.mod")
-	if err != nil {
-		panic(err)
-	}
-	//引入部分依赖于cargen的结构体，避免文档生成时找不到结构体定义
-	cargenPath, err := fileUtil.ProjectPath()
+func (pkgs *Packages) InitPackages(base string, tags ...string) {
+	loader, err := FindLoader(base)
 	if err != nil {
 		panic(err)
 	}
-	cargenFiles, err := fileUtil.GetFilePath(cargenPath, "go.mod")
-	if err != nil {
-		panic(err)
-	}
-	files = append(files, cargenFiles...)
-	for _, file := range files {
-		goModFilePathData, _ := os.ReadFile(file)
-		modFile, _ := modfile.Parse("go.mod", goModFilePathData, nil)
-		path, err := fileUtil.CutPathLast(fileUtil.FixPathSeparator(file), 1)
-		if err != nil {
-			continue
-		}
-		pathChild, err := fileUtil.GetAllPath(path)
-		if err != nil {
-			continue
-		}
-		//获取所有包和结构体定义
-		for _, s := range pathChild {
-			pkg := modFile.Module.Mod.Path + "/" + s[len(path)+1:]
-			pkg = strings.ReplaceAll(pkg, "\\", "/")
-			packages := pkgs.GenPackage(pkg, s)
-			for i := range packages {
-				packages[i].ModPath = path
-				packages[i].FindConfig()
-				*pkgs = append(*pkgs, *packages[i])
-			}
-		}
+	loader.Tags = tags
+	//并发加载所有模块的包和类型信息
+	for _, p := range loader.LoadModules() {
+		pkg := loader.NewPackage(pkgs, p)
+		pkg.FindPkgStruct()
+		pkg.FindPkgEnum()
+		pkg.FindConfig()
+		*pkgs = append(*pkgs, *pkg)
 	}
+	//加载结构体引用的依赖包 如core/controller中的Result
+	loader.Preload(pkgs)
 	//填充字段为结构体的依赖
 	pkgs.FillPkgRelationStruct()
+	//实例化字段中的泛型结构体
+	pkgs.FillPkgGenericStruct()
 }
 
-func (pkgs *Packages) GenPackage(pkgPath, path string) []*Package {
-	astPkg, err := doc.GetPackages(path)
-	if err != nil {
-		fserr, ok := err.(*fs.PathError)
-		if ok {
-			if fserr.Err == syscall.ENOTDIR {
-
-			} else {
-				panic(err)
-			}
-		} else {
-			panic(err)
-		}
-	}
-	var list []*Package
-	//查找结构体定义
-	for _, pkg := range astPkg {
-		p := &Package{Name: pkg.Name, Path: pkgPath, Structs: make(map[string]*Struct), astPkg: pkg, pkgs: pkgs}
-		p.FindPkgStruct()
-		list = append(list, p)
+// FillPkgRelationStruct 设置包内的关联结构体
+func (pkgs *Packages) FillPkgRelationStruct() {
+	for i := range *pkgs {
+		pkgs.fillRelationStruct(&(*pkgs)[i])
 	}
-	return list
 }
 
-// FillPkgRelationStruct 设置包内的关联结构体
-func (pkgs *Packages) FillPkgRelationStruct() {
-	for i, pkg := range *pkgs {
-		for is, sct := range pkg.Structs {
-			for id, fd := range sct.Fields {
-				if !baseTypes.CheckIn(fd.Type) {
-					(*pkgs)[i].Structs[is].Fields[id].Struct = pkgs.FindStructPtr(fd.PkgPath, fd.Pkg, fd.Type)
-				}
-				if !baseTypes.CheckIn(fd.MapInfo.Key.Type) {
-					(*pkgs)[i].Structs[is].Fields[id].MapInfo.Key.Struct = pkgs.FindStructPtr(fd.MapInfo.Key.PkgPath, fd.MapInfo.Key.Pkg, fd.MapInfo.Key.Type)
-				}
-				if !baseTypes.CheckIn(fd.MapInfo.Value.Type) {
-					(*pkgs)[i].Structs[is].Fields[id].MapInfo.Value.Struct = pkgs.FindStructPtr(fd.MapInfo.Value.PkgPath, fd.MapInfo.Value.Pkg, fd.MapInfo.Value.Type)
-				}
+func (pkgs *Packages) fillRelationStruct(pkg *Package) {
+	for is, sct := range pkg.Structs {
+		for id, fd := range sct.Fields {
+			if !baseTypes.CheckIn(fd.Type) {
+				pkg.Structs[is].Fields[id].Struct = pkgs.FindStructPtr(fd.PkgPath, fd.Pkg, fd.Type)
+				pkg.Structs[is].Fields[id].Enum = pkgs.FindEnum(fd.PkgPath, fd.Pkg, fd.Type)
+			}
+			if !baseTypes.CheckIn(fd.MapInfo.Key.Type) {
+				pkg.Structs[is].Fields[id].MapInfo.Key.Struct = pkgs.FindStructPtr(fd.MapInfo.Key.PkgPath, fd.MapInfo.Key.Pkg, fd.MapInfo.Key.Type)
+			}
+			if !baseTypes.CheckIn(fd.MapInfo.Value.Type) {
+				pkg.Structs[is].Fields[id].MapInfo.Value.Struct = pkgs.FindStructPtr(fd.MapInfo.Value.PkgPath, fd.MapInfo.Value.Pkg, fd.MapInfo.Value.Type)
 			}
 		}
 	}
@@ -126,39 +86,49 @@ func (pkgs *Packages) FillPkgRelationStruct() {
 
 // GetApi 获取所有API定义
 func (pkgs *Packages) GetApi() OpenAPI {
-	api := DefaultInfo
+	api := NewOpenAPI()
 	var apiTags = make(map[string]Tag)
 	for _, p := range *pkgs {
 		for _, s := range p.Structs {
 			if len(s.Api) > 0 {
-				ps := strings.Split(s.Pkg.ModPath, string(os.PathSeparator))
-				tag := s.Name
-				for i := len(ps) - 1; i >= 0; i-- {
-					if ps[i] == "api" {
-						break
-					}
-					tag = ps[i] + "-" + tag
-				}
-
-				apiTags[tag] = Tag{Name: tag, Description: s.Des}
 				for _, a := range s.Api {
-					name := a.GetRequestPath()
-					if api.Paths[name] == nil {
-						api.Paths[name] = make(map[string]Method)
+					if a.Hidden {
+						continue
+					}
+					tags := a.GetTags()
+					for _, tag := range tags {
+						if _, ok := apiTags[tag]; !ok || tagDescription(s, tag) != "" {
+							apiTags[tag] = Tag{Name: tag, Description: tagDescription(s, tag)}
+						}
 					}
-					method := Method{Tags: []string{tag}, OperationId: a.GetOperationId(), Summary: a.Summary, api: &api}
+					method := Method{Tags: tags, OperationId: a.GetOperationId(), Summary: a.Summary, Deprecated: a.Deprecated, api: &api}
 					a.FillRequestParams(&method)
 					a.FillResponse(&method)
+					a.FillExample(&method)
+					a.FillErrorResponse(&method)
 					a.FillSecurity(&method)
-					if a.Auth == AuthTypeJWT {
-						api.Components.SetSecurityScheme(a.AuthTo, SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: a.Auth, Description: a.AuthTo})
-					}
 
-					api.Paths[name][strings.ToLower(a.HttpMethod)] = method
+					methods := []string{a.HttpMethod}
+					if a.HttpMethod == MethodAny { //ANY按gin规则展开到所有方法
+						methods = APIMethods
+					}
+					name := a.GetRequestPath()
+					for _, m := range methods {
+						if m == http.MethodConnect { //OpenAPI路径项没有connect操作 仅注册路由
+							continue
+						}
+						if api.Paths[name] == nil {
+							api.Paths[name] = make(map[string]Method)
+						}
+						op := method
+						op.OperationId = a.GetMethodOperationId(m)
+						api.Paths[name][strings.ToLower(m)] = op
+					}
 				}
 			}
 		}
 	}
+	api.FillErrorComponents()
 	for _, tag := range apiTags {
 		api.Tags = append(api.Tags, tag)
 	}
@@ -169,6 +139,14 @@ func (pkgs *Packages) GetApi() OpenAPI {
 	return api
 }
 
+// tagDescription 结构体标签及其版本标签使用结构体注释作为描述
+func tagDescription(s *Struct, tag string) string {
+	if tag == s.GetTag() || strings.HasPrefix(tag, s.GetTag()+"-v") {
+		return s.Des
+	}
+	return ""
+}
+
 // FindInMethodMapParams 从调用链获取返回参数
 func (pkgs *Packages) FindInMethodMapParams(sct *Struct) {
 	if sct == nil {
@@ -213,13 +191,13 @@ func (pkgs *Packages) FindInMethodMapParams(sct *Struct) {
 								sct.Type = st.Type
 							}
 						} else {
-							log.Printf("path too short " + m.Paths[len(m.Paths)-1] + "." + m.Paths[len(m.Paths)-2] + "." + m.Paths[len(m.Paths)-3])
+							log.Println("path too short", m.Paths[len(m.Paths)-1]+"."+m.Paths[len(m.Paths)-2]+"."+m.Paths[len(m.Paths)-3])
 						}
 						break
 					}
 				}
 			} else {
-				log.Printf("path too short " + m.Paths[len(m.Paths)-1] + "." + m.Paths[len(m.Paths)-2])
+				log.Println("path too short", m.Paths[len(m.Paths)-1]+"."+m.Paths[len(m.Paths)-2])
 			}
 			break
 		}
@@ -275,23 +253,45 @@ func (pkgs *Packages) DeepFindParams(idx int, paths []string, field Field) *Stru
 	return nil
 }
 
-func (pkgs *Packages) FindStructPtr(path, pkg, name string) *Struct {
-	for _, p := range *pkgs {
-		if p.Path == path && p.Name == pkg {
-			return p.Structs[name]
+// FindPackage 通过包路径查找包 未加载的依赖包按需加载
+// 包名只在路径为空时使用 别名导入时包名与导入名不一致
+func (pkgs *Packages) FindPackage(path, pkg string) *Package {
+	for i, p := range *pkgs {
+		if p.Path == path && (path != "" || p.Name == pkg) {
+			return &(*pkgs)[i]
+		}
+	}
+	if loader := pkgs.loader(); loader != nil && path != "" {
+		n := len(*pkgs)
+		loader.LoadDependency(pkgs, path)
+		for i := n; i < len(*pkgs); i++ {
+			if (*pkgs)[i].Path == path {
+				return &(*pkgs)[i]
+			}
 		}
 	}
 	return nil
 }
 
-func (pkgs *Packages) FindStruct(path, pkg, name string) Struct {
+func (pkgs *Packages) loader() *Loader {
 	for _, p := range *pkgs {
-		if p.Path == path && p.Name == pkg {
-			s := p.Structs[name]
-			if s != nil {
-				return s.Copy()
-			}
+		if p.loader != nil {
+			return p.loader
 		}
 	}
+	return nil
+}
+
+func (pkgs *Packages) FindStructPtr(path, pkg, name string) *Struct {
+	if p := pkgs.FindPackage(path, pkg); p != nil {
+		return p.Structs[name]
+	}
+	return nil
+}
+
+func (pkgs *Packages) FindStruct(path, pkg, name string) Struct {
+	if s := pkgs.FindStructPtr(path, pkg, name); s != nil {
+		return s.Copy()
+	}
 	return Struct{}
 }
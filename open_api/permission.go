@@ -0,0 +1,19 @@
+package openapi
+
+import "strings"
+
+// PermStart 接口所需权限 perm:order.read,order.write 生成路由时通过PermissionChecker校验
+const PermStart = "perm:"
+
+// analysisPermission 解析权限注解 非权限注解返回false
+func (a *Api) analysisPermission(annotate string) bool {
+	if strings.Index(annotate, PermStart) != 0 {
+		return false
+	}
+	for _, perm := range strings.Split(strings.TrimPrefix(annotate, PermStart), ",") {
+		if perm = strings.TrimSpace(perm); perm != "" {
+			a.Permissions = append(a.Permissions, perm)
+		}
+	}
+	return true
+}
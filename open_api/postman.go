@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/carlos-yuan/cargen/util/convert"
+	"github.com/carlos-yuan/cargen/util/fileUtil"
+)
+
+const (
+	PostmanSchema      = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	PostmanBaseUrl     = "baseUrl" //请求地址变量
+	PostmanBaseUrlVal  = "http://localhost:8080"
+	PostmanTokenSuffix = "Token" //JWT令牌变量后缀 如UserToken
+)
+
+// PostmanCollection Postman v2.1集合
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem 目录或请求 目录时Item不为空
+type PostmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []PostmanItem   `json:"item,omitempty"`
+	Request     *PostmanRequest `json:"request,omitempty"`
+}
+
+type PostmanRequest struct {
+	Method      string            `json:"method"`
+	Description string            `json:"description,omitempty"`
+	Header      []PostmanVariable `json:"header"`
+	Url         PostmanUrl        `json:"url"`
+	Body        *PostmanBody      `json:"body,omitempty"`
+	Auth        *PostmanAuth      `json:"auth,omitempty"`
+}
+
+type PostmanUrl struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []PostmanVariable `json:"query,omitempty"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+type PostmanBody struct {
+	Mode     string              `json:"mode"`
+	Raw      string              `json:"raw,omitempty"`
+	Formdata []PostmanVariable   `json:"formdata,omitempty"`
+	Options  *PostmanBodyOptions `json:"options,omitempty"`
+}
+
+type PostmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type PostmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []PostmanVariable `json:"bearer,omitempty"`
+}
+
+type PostmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// GenPostmanFromPath 通过目录生成Postman集合
+func GenPostmanFromPath(name, des, version, path, out string) {
+	pkgs := Packages{}
+	pkgs.Init(path)
+	collection := pkgs.GetPostman()
+	collection.Info.Name = name
+	collection.Info.Description = des
+	collection.Info.Version = version
+	b, _ := json.Marshal(collection)
+	err := fileUtil.WriteByteFile(out, b)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetPostman 获取Postman集合 按标签分目录
+func (pkgs *Packages) GetPostman() PostmanCollection {
+	collection := PostmanCollection{Info: PostmanInfo{Schema: PostmanSchema}}
+	collection.Variable = append(collection.Variable, PostmanVariable{Key: PostmanBaseUrl, Value: PostmanBaseUrlVal, Type: "string"})
+	folders := make(map[string]*PostmanItem)
+	tokens := make(map[string]bool)
+	for _, p := range *pkgs {
+		for _, s := range p.Structs {
+			if len(s.Api) == 0 {
+				continue
+			}
+			for _, a := range s.Api {
+				if a.Hidden {
+					continue
+				}
+				tag := a.GetTags()[0]
+				folder := folders[tag]
+				if folder == nil {
+					folder = &PostmanItem{Name: tag}
+					folders[tag] = folder
+				}
+				if desc := tagDescription(s, tag); desc != "" {
+					folder.Description = desc
+				}
+				folder.Item = append(folder.Item, a.ToPostmanItems()...)
+				if a.Auth == AuthTypeJWT {
+					tokens[a.GetTokenVariable()] = true
+				}
+			}
+		}
+	}
+	for _, folder := range folders {
+		sort.Slice(folder.Item, func(i, j int) bool {
+			if folder.Item[i].Request.Url.Raw == folder.Item[j].Request.Url.Raw {
+				return folder.Item[i].Request.Method < folder.Item[j].Request.Method
+			}
+			return folder.Item[i].Request.Url.Raw < folder.Item[j].Request.Url.Raw
+		})
+		collection.Item = append(collection.Item, *folder)
+	}
+	sort.Slice(collection.Item, func(i, j int) bool {
+		return collection.Item[i].Name < collection.Item[j].Name
+	})
+	var names []string
+	for name := range tokens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		collection.Variable = append(collection.Variable, PostmanVariable{Key: name, Type: "string"})
+	}
+	return collection
+}
+
+// GetTokenVariable JWT令牌变量名
+func (a *Api) GetTokenVariable() string {
+	name := a.AuthTo
+	if name == "" {
+		name = a.Auth
+	}
+	return convert.FistToLower(name) + PostmanTokenSuffix
+}
+
+// ToPostmanItems 接口转换为Postman请求 参数及请求体与OpenAPI一致 ANY与文档相同展开到各方法
+func (a *Api) ToPostmanItems() []PostmanItem {
+	item := a.toPostmanItem()
+	if a.HttpMethod != MethodAny {
+		return []PostmanItem{item}
+	}
+	var items []PostmanItem
+	for _, m := range APIMethods {
+		if m == http.MethodConnect {
+			continue
+		}
+		req := *item.Request
+		req.Method = m
+		items = append(items, PostmanItem{Name: item.Name, Request: &req})
+	}
+	return items
+}
+
+func (a *Api) toPostmanItem() PostmanItem {
+	method := Method{}
+	a.FillRequestParams(&method)
+	req := &PostmanRequest{Method: strings.ToUpper(a.HttpMethod), Description: a.Summary, Header: []PostmanVariable{}}
+	path := a.GetRequestPath()
+	for _, param := range method.Parameters {
+		v := PostmanVariable{Key: param.Name, Value: param.Schema.ExampleString(), Description: param.Description}
+		switch param.In {
+		case OpenApiInPath:
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", ":"+param.Name)
+			req.Url.Variable = append(req.Url.Variable, v)
+		case OpenApiInHeader:
+			req.Header = append(req.Header, v)
+		case OpenApiInCookie:
+			v.Key = "Cookie"
+			v.Value = param.Name + "=" + v.Value
+			req.Header = append(req.Header, v)
+		default:
+			req.Url.Query = append(req.Url.Query, v)
+		}
+	}
+	req.Url.Host = []string{"{{" + PostmanBaseUrl + "}}"}
+	req.Url.Path = strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range req.Url.Path { //未解析到参数的路径变量
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := seg[1 : len(seg)-1]
+			req.Url.Path[i] = ":" + name
+			req.Url.Variable = append(req.Url.Variable, PostmanVariable{Key: name})
+		}
+	}
+	path = "/" + strings.Join(req.Url.Path, "/")
+	req.Url.Raw = "{{" + PostmanBaseUrl + "}}" + path
+	var query []string
+	for _, q := range req.Url.Query {
+		query = append(query, q.Key+"="+q.Value)
+	}
+	if len(query) > 0 {
+		req.Url.Raw += "?" + strings.Join(query, "&")
+	}
+	for contentType, content := range method.RequestBody.Content {
+		if contentType == "multipart/form-data" { //上传文件使用form-data
+			req.Body = &PostmanBody{Mode: "formdata"}
+			for _, name := range sortedKeys(content.Schema.Properties) {
+				p := content.Schema.Properties[name]
+				v := PostmanVariable{Key: name, Type: "text", Description: p.Description}
+				if p.IsBinary() {
+					v.Type = "file"
+				} else {
+					v.Value = p.ExampleString()
+				}
+				req.Body.Formdata = append(req.Body.Formdata, v)
+			}
+			continue
+		}
+		b, _ := json.MarshalIndent(content.Schema.GetExample(), "", "  ")
+		req.Body = &PostmanBody{Mode: "raw", Raw: string(b), Options: &PostmanBodyOptions{}}
+		req.Body.Options.Raw.Language = "json"
+		req.Header = append(req.Header, PostmanVariable{Key: "Content-Type", Value: contentType})
+	}
+	if a.Auth == AuthTypeJWT {
+		req.Auth = &PostmanAuth{Type: "bearer", Bearer: []PostmanVariable{{Key: "token", Value: "{{" + a.GetTokenVariable() + "}}", Type: "string"}}}
+	}
+	name := a.Summary
+	if name == "" {
+		name = a.Name
+	}
+	return PostmanItem{Name: name, Request: req}
+}
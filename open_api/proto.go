@@ -0,0 +1,332 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/carlos-yuan/cargen/util/fileUtil"
+	"github.com/emicklei/proto"
+)
+
+// protoScalars proto基础类型对应的openapi类型
+var protoScalars = map[string]string{
+	"double":   OpenApiTypeNumber,
+	"float":    OpenApiTypeNumber,
+	"int32":    OpenApiTypeInteger,
+	"int64":    OpenApiTypeInteger,
+	"uint32":   OpenApiTypeInteger,
+	"uint64":   OpenApiTypeInteger,
+	"sint32":   OpenApiTypeInteger,
+	"sint64":   OpenApiTypeInteger,
+	"fixed32":  OpenApiTypeInteger,
+	"fixed64":  OpenApiTypeInteger,
+	"sfixed32": OpenApiTypeInteger,
+	"sfixed64": OpenApiTypeInteger,
+	"bool":     OpenApiTypeBoolean,
+	"string":   OpenApiTypeString,
+	"bytes":    OpenApiTypeString,
+}
+
+// protoWellKnown google.protobuf常用类型 按json映射
+var protoWellKnown = map[string]Property{
+	"google.protobuf.Empty":       {Type: OpenApiTypeObject},
+	"google.protobuf.Any":         {Type: OpenApiTypeObject},
+	"google.protobuf.Struct":      {Type: OpenApiTypeObject},
+	"google.protobuf.Timestamp":   {Type: OpenApiTypeString, Format: "date-time"},
+	"google.protobuf.Duration":    {Type: OpenApiTypeString},
+	"google.protobuf.StringValue": {Type: OpenApiTypeString},
+	"google.protobuf.BoolValue":   {Type: OpenApiTypeBoolean},
+	"google.protobuf.Int32Value":  {Type: OpenApiTypeInteger, Format: "int32"},
+	"google.protobuf.Int64Value":  {Type: OpenApiTypeInteger, Format: "int64"},
+	"google.protobuf.DoubleValue": {Type: OpenApiTypeNumber, Format: "double"},
+}
+
+// GenFromProto 通过biz/<name>/rpc下的proto文件生成rpc接口文档 merge为true时与gin接口合并
+func GenFromProto(name, des, version, path, out string, merge bool) {
+	api, err := GetProtoApi(FindProtoFiles(path))
+	if err != nil {
+		panic(err)
+	}
+	if merge {
+		pkgs := Packages{}
+		pkgs.Init(path)
+		apis := pkgs.GetApi()
+		apis.Merge(api)
+		api = apis
+	}
+	api.Info.Title = name
+	api.Info.Description = des
+	api.Info.Version = version
+	b, _ := json.Marshal(api)
+	err = fileUtil.WriteByteFile(out, b)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindProtoFiles 查找biz/<name>/rpc下的proto文件
+func FindProtoFiles(path string) []string {
+	files, _ := filepath.Glob(filepath.Join(path, "biz", "*", "rpc", "*.proto"))
+	sort.Strings(files)
+	return files
+}
+
+// GetProtoApi 解析proto文件 每个rpc生成一个POST接口 路径为/package.Service/Method
+func GetProtoApi(files []string) (OpenAPI, error) {
+	d := protoDoc{
+		api: OpenAPI{
+			Openapi:    DefaultInfo.Openapi,
+			Paths:      make(ApiPathsMap),
+			Components: Components{Schemas: make(map[string]Property)},
+		},
+		messages: make(map[string]*proto.Message),
+		enums:    make(map[string]*proto.Enum),
+	}
+	for _, file := range files {
+		if err := d.parse(file); err != nil {
+			return d.api, err
+		}
+	}
+	for _, name := range sortedKeys(d.messages) {
+		d.api.Components.Schemas[name] = d.message(name, d.messages[name])
+	}
+	for _, s := range d.services {
+		d.service(s.pkg, s.service)
+	}
+	sort.Slice(d.api.Tags, func(i, j int) bool {
+		return d.api.Tags[i].Name < d.api.Tags[j].Name
+	})
+	return d.api, nil
+}
+
+type protoDoc struct {
+	api      OpenAPI
+	messages map[string]*proto.Message //全名 package.Message
+	enums    map[string]*proto.Enum
+	services []protoService
+}
+
+type protoService struct {
+	pkg     string
+	service *proto.Service
+}
+
+func (d *protoDoc) parse(file string) error {
+	reader, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	definition, err := proto.NewParser(reader).Parse()
+	if err != nil {
+		return err
+	}
+	pkg := ""
+	for _, e := range definition.Elements {
+		if p, ok := e.(*proto.Package); ok {
+			pkg = p.Name
+		}
+	}
+	d.collect(pkg, definition.Elements)
+	for _, e := range definition.Elements {
+		if s, ok := e.(*proto.Service); ok {
+			d.services = append(d.services, protoService{pkg: pkg, service: s})
+		}
+	}
+	return nil
+}
+
+// collect 收集消息及枚举定义 嵌套定义使用Outer.Inner
+func (d *protoDoc) collect(scope string, elements []proto.Visitee) {
+	for _, e := range elements {
+		switch v := e.(type) {
+		case *proto.Message:
+			if v.IsExtend {
+				continue
+			}
+			name := protoJoin(scope, v.Name)
+			d.messages[name] = v
+			d.collect(name, v.Elements)
+		case *proto.Enum:
+			d.enums[protoJoin(scope, v.Name)] = v
+		}
+	}
+}
+
+func (d *protoDoc) service(pkg string, s *proto.Service) {
+	d.api.Tags = append(d.api.Tags, Tag{Name: s.Name, Description: protoComment(s.Comment, nil)})
+	for _, e := range s.Elements {
+		rpc, ok := e.(*proto.RPC)
+		if !ok {
+			continue
+		}
+		method := Method{
+			Tags:        []string{s.Name},
+			Summary:     strings.TrimSpace(strings.TrimPrefix(protoComment(rpc.Comment, rpc.InlineComment), rpc.Name)), //与接口注释一致去掉方法名
+			OperationId: protoJoin(pkg, s.Name+"."+rpc.Name),
+			RequestBody: RequestBody{Content: map[string]Content{"application/json": {Schema: d.typeProperty(pkg, rpc.RequestType)}}},
+			Responses:   map[string]Response{"200": {Description: rpc.ReturnsType, Content: map[string]Content{"application/json": {Schema: d.typeProperty(pkg, rpc.ReturnsType)}}}},
+		}
+		if rpc.StreamsRequest || rpc.StreamsReturns {
+			method.Description = "stream:"
+			if rpc.StreamsRequest {
+				method.Description += " client"
+			}
+			if rpc.StreamsReturns {
+				method.Description += " server"
+			}
+		}
+		d.api.Paths["/"+protoJoin(pkg, s.Name)+"/"+rpc.Name] = map[string]Method{"post": method}
+	}
+}
+
+// message 消息转换为对象 字段注释作为描述
+func (d *protoDoc) message(name string, m *proto.Message) Property {
+	p := Property{Type: OpenApiTypeObject, Description: protoComment(m.Comment, nil), Properties: make(map[string]Property)}
+	var fields func(elements []proto.Visitee)
+	fields = func(elements []proto.Visitee) {
+		for _, e := range elements {
+			switch f := e.(type) {
+			case *proto.NormalField:
+				item := d.typeProperty(name, f.Type)
+				if f.Repeated {
+					elem := item
+					item = Property{Type: OpenApiTypeArray, Items: &elem}
+				}
+				p.Properties[f.Name] = d.describe(item, f.Field)
+				if f.Required {
+					p.Required = append(p.Required, f.Name)
+				}
+			case *proto.MapField:
+				value := d.typeProperty(name, f.Type)
+				p.Properties[f.Name] = d.describe(Property{Type: OpenApiTypeObject, AdditionalProperties: &value}, f.Field)
+			case *proto.OneOfField:
+				p.Properties[f.Name] = d.describe(d.typeProperty(name, f.Type), f.Field)
+			case *proto.Oneof:
+				fields(f.Elements)
+			}
+		}
+	}
+	fields(m.Elements)
+	return p
+}
+
+func (d *protoDoc) describe(p Property, f *proto.Field) Property {
+	if p.Ref != "" { //引用不能有同级描述
+		return p
+	}
+	if desc := protoComment(f.Comment, f.InlineComment); desc != "" { //没有字段注释时保留枚举等类型的描述
+		p.Description = desc
+	}
+	return p
+}
+
+// typeProperty 字段类型转换为属性 消息引用components/schemas 枚举展开
+func (d *protoDoc) typeProperty(scope, typ string) Property {
+	if t, ok := protoScalars[typ]; ok {
+		p := Property{Type: t, Format: typ}
+		if typ == "bytes" {
+			p.Format = "byte"
+		}
+		return p
+	}
+	name := d.resolve(scope, typ)
+	if p, ok := protoWellKnown[name]; ok {
+		return p
+	}
+	if _, ok := d.messages[name]; ok {
+		return Property{Ref: OpenApiSchemasPrefix + name}
+	}
+	if em, ok := d.enums[name]; ok {
+		p := Property{Type: OpenApiTypeInteger, Format: "int32", Description: protoComment(em.Comment, nil)}
+		for _, e := range em.Elements {
+			if f, ok := e.(*proto.EnumField); ok {
+				p.Enum = append(p.Enum, f.Integer)
+				p.EnumVarNames = append(p.EnumVarNames, f.Name)
+				p.EnumDescriptions = append(p.EnumDescriptions, protoComment(f.Comment, f.InlineComment))
+			}
+		}
+		return p
+	}
+	return Property{Type: OpenApiTypeObject, Description: typ}
+}
+
+// resolve 按proto作用域规则查找类型全名 由内向外
+func (d *protoDoc) resolve(scope, typ string) string {
+	if strings.HasPrefix(typ, ".") {
+		return typ[1:]
+	}
+	s := scope
+	for {
+		name := protoJoin(s, typ)
+		if _, ok := d.messages[name]; ok {
+			return name
+		}
+		if _, ok := d.enums[name]; ok {
+			return name
+		}
+		if s == "" {
+			return typ
+		}
+		if i := strings.LastIndex(s, "."); i >= 0 {
+			s = s[:i]
+		} else {
+			s = ""
+		}
+	}
+}
+
+// Merge 合并文档 用于gin接口与rpc接口输出到同一文档
+func (api *OpenAPI) Merge(other OpenAPI) {
+	if api.Paths == nil {
+		api.Paths = make(ApiPathsMap)
+	}
+	for path, methods := range other.Paths {
+		if api.Paths[path] == nil {
+			api.Paths[path] = make(map[string]Method)
+		}
+		for name, method := range methods {
+			api.Paths[path][name] = method
+		}
+	}
+	if api.Components.Schemas == nil {
+		api.Components.Schemas = make(map[string]Property)
+	}
+	for name, schema := range other.Components.Schemas {
+		api.Components.Schemas[name] = schema
+	}
+	tags := make(map[string]bool)
+	for _, tag := range api.Tags {
+		tags[tag.Name] = true
+	}
+	for _, tag := range other.Tags {
+		if !tags[tag.Name] {
+			api.Tags = append(api.Tags, tag)
+		}
+	}
+	sort.Slice(api.Tags, func(i, j int) bool {
+		return api.Tags[i].Name < api.Tags[j].Name
+	})
+}
+
+func protoJoin(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// protoComment 优先使用上方注释 没有时使用行尾注释
+func protoComment(comment, inline *proto.Comment) string {
+	for _, c := range []*proto.Comment{comment, inline} {
+		if c != nil {
+			if msg := strings.TrimSpace(strings.Join(c.Lines, " ")); msg != "" {
+				return msg
+			}
+		}
+	}
+	return ""
+}
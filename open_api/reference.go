@@ -0,0 +1,366 @@
+package openapi
+
+import (
+	"bytes"
+	"html/template"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carlos-yuan/cargen/util/convert"
+	"github.com/carlos-yuan/cargen/util/fileUtil"
+)
+
+const (
+	RefFormatMarkdown = "md"
+	RefFormatHtml     = "html"
+
+	RefIndexName = "index" //首页文件名
+	RefEnumName  = "enums" //字典枚举文件名
+
+	refDeep = 5 //嵌套结构体展开深度
+)
+
+// Reference 接口参考文档 每个标签一页
+type Reference struct {
+	Title   string
+	Des     string
+	Version string
+	Pages   []RefPage
+	Enums   []*RefEnum
+	enums   map[string]*RefEnum
+}
+
+type RefPage struct {
+	Name string //标签
+	File string //文件名 不含后缀
+	Des  string
+	Apis []RefApi
+}
+
+type RefApi struct {
+	Summary     string
+	Description string
+	Method      string
+	Path        string
+	Auth        string
+	Deprecated  bool
+	Permissions []string
+	Params      []RefField
+	Response    []RefField
+}
+
+type RefField struct {
+	Name     string //参数名 嵌套字段为user.name
+	Type     string
+	In       string
+	Required bool
+	Validate string
+	Comment  string
+	Enum     string //字典枚举锚点
+}
+
+type RefEnum struct {
+	Id     string //锚点 包名-枚举名
+	Name   string
+	Pkg    string
+	Des    string
+	Values []EnumValue
+}
+
+// GenReferenceFromPath 通过目录生成Markdown或HTML参考文档 out为输出目录
+func GenReferenceFromPath(name, des, version, path, out, format string) {
+	pkgs := Packages{}
+	pkgs.Init(path)
+	ref := pkgs.GetReference()
+	ref.Title = name
+	ref.Des = des
+	ref.Version = version
+	var files map[string]string
+	if format == RefFormatHtml {
+		files = ref.Html()
+	} else {
+		files = ref.Markdown()
+	}
+	for file, content := range files {
+		err := fileUtil.WriteByteFile(filepath.Join(out, file), []byte(content))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// GetReference 获取参考文档 标签与GetApi一致
+func (pkgs *Packages) GetReference() *Reference {
+	ref := &Reference{enums: make(map[string]*RefEnum)}
+	pages := make(map[string]*RefPage)
+	for _, p := range *pkgs {
+		for _, s := range p.Structs {
+			if len(s.Api) == 0 {
+				continue
+			}
+			for _, a := range s.Api {
+				if a.Hidden {
+					continue
+				}
+				tag := a.GetTags()[0]
+				page := pages[tag]
+				if page == nil {
+					page = &RefPage{Name: tag, File: strings.Trim(tag, "-")}
+					pages[tag] = page
+				}
+				if desc := tagDescription(s, tag); desc != "" {
+					page.Des = desc
+				}
+				page.Apis = append(page.Apis, ref.api(a))
+			}
+		}
+	}
+	for _, page := range pages {
+		sort.Slice(page.Apis, func(i, j int) bool {
+			if page.Apis[i].Path == page.Apis[j].Path {
+				return page.Apis[i].Method < page.Apis[j].Method
+			}
+			return page.Apis[i].Path < page.Apis[j].Path
+		})
+		ref.Pages = append(ref.Pages, *page)
+	}
+	sort.Slice(ref.Pages, func(i, j int) bool {
+		return ref.Pages[i].Name < ref.Pages[j].Name
+	})
+	sort.Slice(ref.Enums, func(i, j int) bool {
+		return ref.Enums[i].Id < ref.Enums[j].Id
+	})
+	return ref
+}
+
+func (ref *Reference) api(a Api) RefApi {
+	ra := RefApi{Summary: a.Summary, Description: a.Description, Method: strings.ToUpper(a.HttpMethod), Path: a.GetRequestPath(), Deprecated: a.Deprecated, Permissions: a.Permissions}
+	if a.Auth != "" {
+		ra.Auth = a.Auth
+		if a.AuthTo != "" {
+			ra.Auth += ":" + a.AuthTo
+		}
+	}
+	if ra.Summary == "" {
+		ra.Summary = a.Name
+	}
+	if a.Params != nil {
+		for _, f := range a.Params.Fields {
+			ra.Params = append(ra.Params, ref.fields("", f, 0)...)
+		}
+	}
+	if a.Response != nil {
+		if baseTypes.CheckIn(a.Response.Name) {
+			ra.Response = append(ra.Response, RefField{Type: a.Response.Name, Comment: a.Response.Des})
+		} else {
+			for _, f := range a.Response.Fields {
+				ra.Response = append(ra.Response, ref.fields("", f, 0)...)
+			}
+		}
+	}
+	return ra
+}
+
+// fields 字段及展开的嵌套结构体字段
+func (ref *Reference) fields(prefix string, f Field, storey int) []RefField {
+	if f.Name != "" && convert.FistIsLower(f.Name) || storey > refDeep {
+		return nil
+	}
+	if f.Name == "" && f.Struct != nil { //组合结构体字段平铺
+		var list []RefField
+		for _, sf := range f.Struct.Fields {
+			list = append(list, ref.fields(prefix, sf, storey+1)...)
+		}
+		return list
+	}
+	name := f.ParamName
+	if name == "" {
+		name = convert.FistToLower(f.Name)
+	}
+	rf := RefField{Name: prefix + name, Type: f.Type, In: f.GetOpenApiIn(), Required: f.IsRequired(), Validate: f.Validate, Comment: f.Comment}
+	if rf.In == TagParamJson {
+		rf.In = "body"
+	}
+	switch {
+	case f.Enum != nil:
+		rf.Enum = ref.enum(f)
+	case f.Struct != nil && !baseTypes.CheckIn(f.Type):
+		rf.Type = f.Struct.Name
+	case f.Type == "map":
+		rf.Type = "map[" + f.MapInfo.Key.Type + "]" + f.MapInfo.Value.Type
+	}
+	if f.Array {
+		rf.Type = "[]" + rf.Type
+	}
+	list := []RefField{rf}
+	if f.Struct != nil && f.Enum == nil {
+		for _, sf := range f.Struct.Fields {
+			list = append(list, ref.fields(rf.Name+".", sf, storey+1)...)
+		}
+	}
+	return list
+}
+
+// enum 记录字段的字典枚举 返回锚点
+func (ref *Reference) enum(f Field) string {
+	id := strings.ToLower(f.Pkg + "-" + f.Enum.Name)
+	if ref.enums[id] == nil {
+		ref.enums[id] = &RefEnum{Id: id, Name: f.Enum.Name, Pkg: f.Pkg, Des: f.Enum.Des, Values: f.Enum.Values}
+		ref.Enums = append(ref.Enums, ref.enums[id])
+	}
+	return id
+}
+
+// Markdown 生成Markdown文档 map[文件名]内容
+func (ref *Reference) Markdown() map[string]string {
+	files := make(map[string]string)
+	var index strings.Builder
+	index.WriteString("# " + ref.Title + "\n\n")
+	if ref.Des != "" {
+		index.WriteString(ref.Des + "\n\n")
+	}
+	if ref.Version != "" {
+		index.WriteString("Version: " + ref.Version + "\n\n")
+	}
+	for _, page := range ref.Pages {
+		index.WriteString("- [" + page.Name + "](" + page.File + ".md) " + mdCell(page.Des) + "\n")
+		files[page.File+".md"] = ref.markdownPage(page)
+	}
+	if len(ref.Enums) > 0 {
+		index.WriteString("- [" + RefEnumName + "](" + RefEnumName + ".md)\n")
+		files[RefEnumName+".md"] = ref.markdownEnums()
+	}
+	files[RefIndexName+".md"] = index.String()
+	return files
+}
+
+func (ref *Reference) markdownPage(page RefPage) string {
+	var sb strings.Builder
+	sb.WriteString("# " + page.Name + "\n\n")
+	if page.Des != "" {
+		sb.WriteString(page.Des + "\n\n")
+	}
+	for _, a := range page.Apis {
+		sb.WriteString("## " + a.Summary + "\n\n")
+		sb.WriteString("`" + a.Method + " " + a.Path + "`\n\n")
+		if a.Deprecated {
+			sb.WriteString("> Deprecated\n\n")
+		}
+		if a.Description != "" {
+			sb.WriteString(a.Description + "\n\n")
+		}
+		if a.Auth != "" {
+			sb.WriteString("Auth: `" + a.Auth + "`\n\n")
+		}
+		if len(a.Permissions) > 0 {
+			sb.WriteString("Permissions: `" + strings.Join(a.Permissions, "`, `") + "`\n\n")
+		}
+		if len(a.Params) > 0 {
+			sb.WriteString("### Parameters\n\n| Name | In | Type | Required | Validate | Comment |\n| --- | --- | --- | --- | --- | --- |\n")
+			for _, f := range a.Params {
+				required := ""
+				if f.Required {
+					required = "yes"
+				}
+				sb.WriteString("| " + mdCell(f.Name) + " | " + f.In + " | " + mdType(f) + " | " + required + " | " + mdCell(f.Validate) + " | " + mdCell(f.Comment) + " |\n")
+			}
+			sb.WriteString("\n")
+		}
+		if len(a.Response) > 0 {
+			sb.WriteString("### Response\n\n| Name | Type | Comment |\n| --- | --- | --- |\n")
+			for _, f := range a.Response {
+				sb.WriteString("| " + mdCell(f.Name) + " | " + mdType(f) + " | " + mdCell(f.Comment) + " |\n")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func (ref *Reference) markdownEnums() string {
+	var sb strings.Builder
+	sb.WriteString("# " + RefEnumName + "\n\n")
+	for _, em := range ref.Enums {
+		sb.WriteString("<a id=\"" + em.Id + "\"></a>\n\n## " + em.Pkg + "." + em.Name + "\n\n")
+		if em.Des != "" && em.Des != em.Name {
+			sb.WriteString(em.Des + "\n\n")
+		}
+		sb.WriteString("| Value | Name | Label |\n| --- | --- | --- |\n")
+		for _, v := range em.Values {
+			sb.WriteString("| " + strconv.FormatInt(v.Value, 10) + " | " + v.Name + " | " + mdCell(v.Label) + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func mdType(f RefField) string {
+	if f.Enum != "" {
+		return "[" + mdCell(f.Type) + "](" + RefEnumName + ".md#" + f.Enum + ")"
+	}
+	return mdCell(f.Type)
+}
+
+// mdCell 表格单元格转义
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// Html 生成HTML文档 map[文件名]内容
+func (ref *Reference) Html() map[string]string {
+	files := make(map[string]string)
+	render := func(name string, data any) string {
+		var buf bytes.Buffer
+		err := refTemplate.ExecuteTemplate(&buf, name, data)
+		if err != nil {
+			panic(err)
+		}
+		return buf.String()
+	}
+	for _, page := range ref.Pages {
+		files[page.File+".html"] = render("page", map[string]any{"Ref": ref, "Page": page})
+	}
+	if len(ref.Enums) > 0 {
+		files[RefEnumName+".html"] = render("enums", ref)
+	}
+	files[RefIndexName+".html"] = render("index", ref)
+	return files
+}
+
+var refTemplate = template.Must(template.New("reference").Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.}}</title>
+<style>body{font-family:sans-serif;max-width:1100px;margin:auto;padding:0 16px}table{border-collapse:collapse;width:100%;margin-bottom:16px}th,td{border:1px solid #ddd;padding:4px 8px;text-align:left}code{background:#f4f4f4;padding:2px 4px}</style>
+</head><body>{{end}}
+{{define "type"}}{{if .Enum}}<a href="enums.html#{{.Enum}}">{{.Type}}</a>{{else}}{{.Type}}{{end}}{{end}}
+{{define "index"}}{{template "head" .Title}}
+<h1>{{.Title}}</h1>{{if .Des}}<p>{{.Des}}</p>{{end}}{{if .Version}}<p>Version: {{.Version}}</p>{{end}}
+<ul>{{range .Pages}}<li><a href="{{.File}}.html">{{.Name}}</a> {{.Des}}</li>{{end}}{{if .Enums}}<li><a href="enums.html">enums</a></li>{{end}}</ul>
+</body></html>{{end}}
+{{define "page"}}{{template "head" .Page.Name}}
+<p><a href="index.html">{{.Ref.Title}}</a></p>
+<h1>{{.Page.Name}}</h1>{{if .Page.Des}}<p>{{.Page.Des}}</p>{{end}}
+{{range .Page.Apis}}<h2>{{.Summary}}</h2>
+<p><code>{{.Method}} {{.Path}}</code></p>{{if .Deprecated}}<p><strong>Deprecated</strong></p>{{end}}{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .Auth}}<p>Auth: <code>{{.Auth}}</code></p>{{end}}{{if .Permissions}}<p>Permissions: {{range .Permissions}}<code>{{.}}</code> {{end}}</p>{{end}}
+{{if .Params}}<h3>Parameters</h3>
+<table><tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Validate</th><th>Comment</th></tr>
+{{range .Params}}<tr><td>{{.Name}}</td><td>{{.In}}</td><td>{{template "type" .}}</td><td>{{if .Required}}yes{{end}}</td><td>{{.Validate}}</td><td>{{.Comment}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Response}}<h3>Response</h3>
+<table><tr><th>Name</th><th>Type</th><th>Comment</th></tr>
+{{range .Response}}<tr><td>{{.Name}}</td><td>{{template "type" .}}</td><td>{{.Comment}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}</body></html>{{end}}
+{{define "enums"}}{{template "head" "enums"}}
+<p><a href="index.html">{{.Title}}</a></p>
+<h1>enums</h1>
+{{range .Enums}}<h2 id="{{.Id}}">{{.Pkg}}.{{.Name}}</h2>{{if ne .Des .Name}}<p>{{.Des}}</p>{{end}}
+<table><tr><th>Value</th><th>Name</th><th>Label</th></tr>
+{{range .Values}}<tr><td>{{.Value}}</td><td>{{.Name}}</td><td>{{.Label}}</td></tr>
+{{end}}</table>
+{{end}}</body></html>{{end}}
+`))
@@ -2,6 +2,8 @@ package openapi
 
 import (
 	"go/ast"
+	"os"
+	"strings"
 
 	"github.com/carlos-yuan/cargen/util/convert"
 )
@@ -17,6 +19,10 @@ type Struct struct {
 	Methods   []StructMethod    `json:"methods"` //方法列表 包括接口的方法
 	Api       []Api             //所有API接口信息
 	Pkg       *Package
+
+	TypeParams []string `json:"typeParams"` //泛型类型参数名 Page[T any]中的T
+	TypeArgs   []Field  `json:"typeArgs"`   //泛型实例化时的类型 Page[User]中的User
+	Origin     string   `json:"origin"`     //泛型实例化前的结构体名称
 }
 
 type MethodMap struct {
@@ -40,6 +46,10 @@ func (sct Struct) Copy() Struct {
 		Methods:   sct.Methods,
 		Api:       sct.Api,
 		Pkg:       sct.Pkg,
+
+		TypeParams: sct.TypeParams,
+		TypeArgs:   sct.TypeArgs,
+		Origin:     sct.Origin,
 	}
 	for _, field := range sct.Fields {
 		s.Fields = append(s.Fields, field)
@@ -55,6 +65,19 @@ type StructMethod struct {
 	Returns []Field //返回值
 }
 
+// GetTag 接口分组标签 模块路径中api目录之后的部分加结构体名
+func (sct *Struct) GetTag() string {
+	ps := strings.Split(sct.Pkg.ModPath, string(os.PathSeparator))
+	tag := sct.Name
+	for i := len(ps) - 1; i >= 0; i-- {
+		if ps[i] == "api" {
+			break
+		}
+		tag = ps[i] + "-" + tag
+	}
+	return tag
+}
+
 func (sct *Struct) GetField() *Field {
 	for _, field := range sct.Fields {
 		if field.Name == sct.Field {
@@ -81,6 +104,7 @@ func (sct *Struct) FieldFromInterfaceAstField(fd *ast.Field) Field {
 		f.In, f.ParamName, f.Validate = GetTagInfo(f.Tag)
 	}
 	f.Comment = FormatComment(fd.Comment)
+	f.FillExample()
 	return f
 }
 
@@ -110,7 +134,7 @@ func (sct *Struct) GetStructFromAstStructType(s *ast.StructType) {
 	if s != nil {
 		for _, fd := range s.Fields.List {
 			f := sct.FieldFromAstField(fd)
-			if !baseTypes.CheckIn(f.Type) {
+			if !baseTypes.CheckIn(f.Type) && f.In != TagParamFile {
 				field := GetExprInfo(fd.Type)
 				if field.Type == ExprStruct {
 					if f.Struct == nil {
@@ -127,14 +151,17 @@ func (sct *Struct) GetStructFromAstStructType(s *ast.StructType) {
 				} else {
 					f.Array = field.Array
 					f.Type = field.Type
-					f.Pkg = field.Pkg
-					if f.Pkg != "" {
-						f.PkgPath = sct.Imports[f.Pkg]
-					} else {
-						f.Pkg = sct.Pkg.Name
-						f.PkgPath = sct.Pkg.Path
+					if f.PkgPath == "" { //没有类型信息时通过导入信息查找
+						f.Pkg = field.Pkg
+						if f.Pkg != "" {
+							f.PkgPath = sct.Imports[f.Pkg]
+						} else {
+							f.Pkg = sct.Pkg.Name
+							f.PkgPath = sct.Pkg.Path
+						}
 					}
-					f.Struct = sct.Pkg.pkgs.FindStructPtr(f.PkgPath, f.Pkg, f.Type)
+					f.Struct = sct.Pkg.pkgs.Instantiate(sct.Pkg.pkgs.FindStructPtr(f.PkgPath, f.Pkg, f.Type), f.TypeArgs)
+					f.Enum = sct.Pkg.pkgs.FindEnum(f.PkgPath, f.Pkg, f.Type)
 				}
 			}
 			sct.Fields = append(sct.Fields, f)
@@ -164,20 +191,56 @@ func (sct *Struct) FieldFromAstField(fd *ast.Field) Field {
 	if f.Pkg != "" {
 		f.PkgPath = sct.Imports[f.Pkg]
 	}
+	if sct.Pkg != nil && !baseTypes.CheckIn(f.Type) { //类型信息中的包优先
+		if name, path := sct.Pkg.TypePkg(fd.Type); path != "" {
+			f.Pkg = name
+			f.PkgPath = path
+		}
+	}
 	if f.MapInfo.Key.Pkg != "" {
 		f.MapInfo.Key.PkgPath = sct.Imports[f.MapInfo.Key.Pkg]
 	}
 	if f.MapInfo.Value.Pkg != "" {
 		f.MapInfo.Value.PkgPath = sct.Imports[f.MapInfo.Value.Pkg]
 	}
+	sct.resolveTypeArgs(f.TypeArgs)
 	if fd.Tag != nil {
 		f.Tag = fd.Tag.Value
 		f.In, f.ParamName, f.Validate = GetTagInfo(f.Tag)
 	}
+	if at, ok := fd.Type.(*ast.ArrayType); ok && f.In == TagParamFile { //多文件[][]byte
+		if _, ok := at.Elt.(*ast.ArrayType); ok {
+			f.Type = "[]" + f.Type
+		}
+	}
 	f.Comment = FormatComment(fd.Comment)
+	f.FillExample()
 	return f
 }
 
+// resolveTypeArgs 补充泛型类型参数的包信息 结构体自身的类型参数保持原样等待实例化时替换
+func (sct *Struct) resolveTypeArgs(args []Field) {
+	for i := range args {
+		if args[i].Pkg != "" {
+			args[i].PkgPath = sct.Imports[args[i].Pkg]
+		} else if !baseTypes.CheckIn(args[i].Type) && !sct.IsTypeParam(args[i].Type) && sct.Pkg != nil {
+			args[i].Pkg = sct.Pkg.Name
+			args[i].PkgPath = sct.Pkg.Path
+		}
+		sct.resolveTypeArgs(args[i].TypeArgs)
+	}
+}
+
+// IsTypeParam 是否为结构体的泛型类型参数
+func (sct *Struct) IsTypeParam(name string) bool {
+	for _, param := range sct.TypeParams {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
 // ToProperty 找到属性
 // deep 递归层数 max递归深度
 func (sct Struct) ToProperty() Property {
@@ -197,7 +260,7 @@ func (sct Struct) ToProperty() Property {
 		if field.Name != "" && convert.FistIsLower(field.Name) { //小写开头的隐藏字段去掉
 			continue
 		}
-		pps = append(pps, field.ToProperty(0, 5)...)
+		pps = append(pps, field.ToResponseProperty(0, 5)...)
 
 	}
 	var p = Property{Properties: make(map[string]Property)}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/cmd/gen"
+)
+
+// routerTestFiles 路由生成测试控制器 二进制及流式下载指定Content-Type
+var routerTestFiles = map[string]string{
+	"api/file.go": `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type File struct {
+	ctl.ControllerContext
+}
+
+// Logo 图标
+// @GET|bytes:image/png
+func (t *File) Logo(ctx ctl.ControllerContext) ([]byte, error) {
+	return []byte("png"), nil
+}
+
+// Export 导出
+// @GET|stream:text/csv
+func (t *File) Export(ctx ctl.ControllerContext) (*ctl.Stream, error) {
+	return nil, nil
+}
+`,
+	"router/router.go": "package router\n\nimport ctl \"github.com/carlos-yuan/cargen/core/controller\"\n\nvar routerList ctl.GinRegisterList\n",
+}
+
+func TestCreateWebRouter(t *testing.T) {
+	dir := writeCargenModule(t, routerTestFiles)
+	gen.CreateWebRouter(dir, gen.WebGin)
+	b, err := os.ReadFile(filepath.Join(dir, "router", "file.gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(b)
+	for _, want := range []string{"res := ctl.HandleNoParams(c, t.Logo)\n\t\t\t\tctl.GinBytes(ctx, res, `image/png`)", "ctl.GinStream(ctx, ctl.HandleNoParams(c, t.Export), `text/csv`)"} {
+		if !strings.Contains(src, want) {
+			t.Fatalf("router missing %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "Content-Type") {
+		t.Fatalf("content type set before the handler result:\n%s", src)
+	}
+	goVet(t, dir)
+}
+
+func TestCreateHertzRouter(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{
+		"api/file.go":      strings.Split(routerTestFiles["api/file.go"], "// Export")[0],
+		"api/order.go":     errorTestController,
+		"router/router.go": "package router\n\nimport ctl \"github.com/carlos-yuan/cargen/core/controller\"\n\nvar (\n\trouterList ctl.HertzRegisterList\n\ttokenMap   map[string]ctl.Token\n)\n",
+	})
+	gen.CreateWebRouter(dir, gen.WebHertz)
+	b, err := os.ReadFile(filepath.Join(dir, "router", "file.gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "ctl.HertzBytes(rc, res, `image/png`)") {
+		t.Fatalf("hertz router missing bytes render:\n%s", b)
+	}
+	goVet(t, dir, "-tags", "hertz")
+}
+
+func TestCreateStdRouter(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{
+		"api/order.go":     errorTestController,
+		"router/router.go": "package router\n\nimport ctl \"github.com/carlos-yuan/cargen/core/controller\"\n\nvar (\n\trouterList ctl.StdRegisterList\n\ttokenMap   map[string]ctl.Token\n)\n",
+	})
+	gen.CreateWebRouter(dir, gen.WebStd)
+	goVet(t, dir)
+	conflict := strings.Replace(errorTestController, "// @GET\n", "// @GET|{id}\n", 1) //Ping与Detail同为GET {id}
+	dir = writeCargenModule(t, map[string]string{"api/order.go": conflict, "router/router.go": "package router\n"})
+	defer func() {
+		if rec := recover(); rec == nil || !strings.Contains(fmt.Sprint(rec), "Order.Ping") {
+			t.Fatalf("std route conflict not reported: %v", rec)
+		}
+	}()
+	gen.CreateWebRouter(dir, gen.WebStd)
+}
@@ -0,0 +1,23 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	e "github.com/carlos-yuan/cargen/core/error"
+)
+
+func TestTypedHandler(t *testing.T) {
+	ctx := ctl.NewGinContext(nil)
+	res := ctl.HandleNoParams(ctx, func(ctx ctl.ControllerContext) (int, error) {
+		return 1, nil
+	})
+	if res.Code != 0 || res.Data != 1 {
+		t.Fatalf("typed handler result not wrapped: %+v", res)
+	}
+	res = ctl.Return(ctx, nil, fmt.Errorf("create: %w", e.ParamsValidatorError))
+	if res.Code != 500 || res.Msg != e.ParamsValidatorError.Msg {
+		t.Fatalf("typed handler error not converted: %+v", res)
+	}
+}
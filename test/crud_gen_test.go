@@ -0,0 +1,82 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/cmd/gen"
+)
+
+// crudQueryTestFile gorm/gen生成的query 方法签名与gen一致 用于编译生成的服务
+const crudQueryTestFile = `package query
+
+import (
+	"context"
+
+	"demo/orm/shop/model"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+type Query struct {
+	User user
+}
+
+type user struct {
+	ID        field.Int64
+	Name      field.String
+	Phone     field.String
+	CreatedAt field.Time
+}
+
+func (u user) WithContext(ctx context.Context) IUserDo {
+	return nil
+}
+
+type IUserDo interface {
+	Where(conds ...gen.Condition) IUserDo
+	Order(conds ...field.Expr) IUserDo
+	First() (*model.User, error)
+	Create(values ...*model.User) error
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	Delete(models ...*model.User) (result gen.ResultInfo, err error)
+	FindByPage(offset int, limit int) (result []*model.User, count int64, err error)
+	ReplaceDB(db *gorm.DB)
+}
+`
+
+func TestCrudGen(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{
+		"orm/shop/model/user.gen.go": "package model\n\nimport \"time\"\n\nconst TableNameUser = \"user\"\n\ntype User struct {\n" +
+			"\tID int64 `gorm:\"column:id;primaryKey;autoIncrement:true;comment:编号\" json:\"id\"`\n" +
+			"\tName string `gorm:\"column:name;type:varchar(32);not null;comment:名称\" json:\"name\"`\n" +
+			"\tPhone *string `gorm:\"column:phone;type:varchar(11);comment:手机号\" json:\"phone\"`\n" +
+			"\tCreatedAt *time.Time `gorm:\"column:created_at;comment:创建时间\" json:\"createdAt\"`\n}\n",
+		"orm/shop/query/gen.go": crudQueryTestFile,
+		"orm/shop/query/user.go": "package query\n\nimport \"context\"\n\ntype UserDao struct {\n\tIUserDo\n}\n\n" +
+			"func (q *Query) GetUserDao(ctx context.Context) UserDao {\n\treturn UserDao{q.User.WithContext(ctx)}\n}\n",
+	})
+	gen.CrudGen(dir, "shop", "admin", []string{"user"})
+	ctl, err := os.ReadFile(filepath.Join(dir, "api/admin/user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := os.ReadFile(filepath.Join(dir, "service/user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"ctl.Paging\n\tPhone *string `form:\"phone\"`", "`json:\"name\" validate:\"required,max=32\"`",
+		"`json:\"phone\" validate:\"omitempty,max=11\"`", "// @PUT|{id}", "*ctl.PageList[UserInfo]"} {
+		if !strings.Contains(string(ctl), want) {
+			t.Fatalf("controller missing %q:\n%s", want, ctl)
+		}
+	}
+	if strings.Contains(string(ctl), "CreatedAt *time.Time `form") || !strings.Contains(string(svc), "s.User.Phone.Eq(*filter.Phone)") {
+		t.Fatalf("unexpected filters:\n%s\n%s", ctl, svc)
+	}
+	goVet(t, dir)
+}
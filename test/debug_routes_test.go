@@ -0,0 +1,27 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/core/config"
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	routes := ctl.GinRegisterList{{Method: http.MethodGet, Group: "/api/user/", Path: "order/list",
+		Meta: ctl.RouteMeta{Controller: "Order", Func: "List", Permissions: []string{"order.read"}}, Handles: []gin.HandlerFunc{func(ctx *gin.Context) {}}}}
+	for env, code := range map[string]int{config.EnvDev: http.StatusOK, config.EnvPro: http.StatusNotFound} {
+		g := gin.New()
+		routes.LoadRoute(g)
+		routes.DebugRoutes(g, env)
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ctl.DebugRoutesPath, nil))
+		if w.Code != code || code == http.StatusOK && !strings.Contains(w.Body.String(), `"path":"/api/user/order/list"`) {
+			t.Fatalf("%s: %d %s", env, w.Code, w.Body.String())
+		}
+	}
+}
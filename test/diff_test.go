@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/cmd/gen"
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+// diffTestDoc 比较用文档 User包含一个字符串字段 可附加用户查询接口的参数
+func diffTestDoc(name string, params ...openapi.Parameter) openapi.OpenAPI {
+	user := func(name string) openapi.Property {
+		return openapi.Property{Type: openapi.OpenApiTypeObject, Properties: map[string]openapi.Property{
+			name: {Type: openapi.OpenApiTypeString},
+		}}
+	}
+	return openapi.OpenAPI{
+		Paths: openapi.ApiPathsMap{"/api/user/get": {"get": openapi.Method{
+			Parameters: params,
+			Responses: map[string]openapi.Response{"200": {Content: map[string]openapi.Content{
+				"application/json": {Schema: openapi.Property{Ref: "#/components/schemas/User"}},
+			}}},
+		}}},
+		Components: openapi.Components{Schemas: map[string]openapi.Property{"User": user(name)}},
+	}
+}
+
+func TestOpenApiDiff(t *testing.T) {
+	changes := openapi.Diff(diffTestDoc("name"), diffTestDoc("name"))
+	if len(changes) != 0 {
+		t.Fatal(changes.Report())
+	}
+	changes = openapi.Diff(diffTestDoc("name"), diffTestDoc("userName"))
+	if !changes.HasBreaking() || len(changes) != 2 {
+		t.Fatal(changes.Report())
+	}
+	changes = openapi.Diff(diffTestDoc("name"), diffTestDoc("name", openapi.Parameter{Name: "id", In: openapi.OpenApiInQuery}))
+	if changes.HasBreaking() || len(changes) != 1 {
+		t.Fatal(changes.Report())
+	}
+	changes = openapi.Diff(diffTestDoc("name"), diffTestDoc("name", openapi.Parameter{Name: "id", In: openapi.OpenApiInQuery, Required: true}))
+	if !changes.HasBreaking() {
+		t.Fatal(changes.Report())
+	}
+}
+
+func TestDocDiff(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, doc openapi.OpenAPI) string {
+		b, _ := json.Marshal(doc)
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	oldFile := write("old.json", diffTestDoc("name"))
+	var out strings.Builder
+	if !gen.DocDiff(&out, oldFile, write("add.json", diffTestDoc("name", openapi.Parameter{Name: "id", In: openapi.OpenApiInQuery}))) {
+		t.Fatalf("compatible change reported as breaking: %s", out.String())
+	}
+	out.Reset()
+	if gen.DocDiff(&out, oldFile, write("rename.json", diffTestDoc("userName"))) || !strings.Contains(out.String(), "name") {
+		t.Fatalf("breaking change not reported: %s", out.String())
+	}
+}
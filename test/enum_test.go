@@ -2,7 +2,59 @@ package test
 
 import (
 	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
 )
 
 func TestCreateEnum(t *testing.T) {
 }
+
+const enumTestFile = `package enum
+
+import "strconv"
+
+type OrderStatus int
+
+const (
+	OrderStatusWait OrderStatus = 1 //待支付
+	OrderStatusPaid OrderStatus = 2 //已支付
+)
+
+func (t OrderStatus) String() string {
+	switch t {
+	case OrderStatusWait:
+		return "待支付"
+	case OrderStatusPaid:
+		return "已支付"
+	}
+	return ""
+}
+
+func (t OrderStatus) MarshalJSON() ([]byte, error) {
+	return []byte("{\"key\":" + strconv.Itoa(int(t)) + ",\"val\":\"" + t.String() + "\"}"), nil
+}
+`
+
+func TestOpenApiEnum(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":       "module demo\n\ngo 1.21\n",
+		"enum/enum.go": enumTestFile,
+	})
+	pkgs := openapi.Packages{}
+	pkgs.InitPackages(dir)
+	em := pkgs.FindEnum("demo/enum", "enum", "OrderStatus")
+	if em == nil {
+		t.Fatal("enum OrderStatus not found")
+	}
+	if !em.Object || len(em.Values) != 2 || em.Values[1].Label != "已支付" {
+		t.Errorf("unexpected enum %+v", em)
+	}
+	p := em.ToProperty(true)
+	if p.Type != openapi.OpenApiTypeObject || len(p.Properties[openapi.EnumKeyName].EnumVarNames) != 2 {
+		t.Errorf("unexpected response schema %+v", p)
+	}
+	p = em.ToProperty(false)
+	if p.Type != openapi.OpenApiTypeInteger || len(p.Enum) != 2 || p.EnumDescriptions[0] != "待支付" {
+		t.Errorf("unexpected request schema %+v", p)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+const errorTestController = `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type Order struct {
+	ctl.ControllerContext
+}
+
+type OrderReq struct {
+	Id int64 ` + "`uri:\"id\"`" + `
+}
+
+// Detail 订单详情
+// @GET|{id}|JWT|perm:order.read
+func (t *Order) Detail(ctx ctl.ControllerContext, req *OrderReq) (string, error) {
+	return "", nil
+}
+
+// Ping 心跳
+// @GET
+func (t *Order) Ping(ctx ctl.ControllerContext) (bool, error) {
+	return true, nil
+}
+`
+
+func TestOpenApiErrorResponse(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{"api/order.go": errorTestController})
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+	api := pkgs.GetApi()
+	detail := api.Paths["/demo/order/{id}"]["get"].Responses
+	for status, name := range map[string]string{"400": "BadRequest", "401": "Unauthorized", "403": "Forbidden", "500": "InternalServerError"} {
+		if detail[status].Ref != "#/components/responses/"+name {
+			t.Fatalf("error response %s not documented: %+v", status, detail)
+		}
+	}
+	ping := api.Paths["/demo/order/ping"]["get"].Responses
+	if _, ok := ping["400"]; ok || ping["500"].Ref == "" || len(ping) != 2 {
+		t.Fatalf("unexpected error responses for api without params: %+v", ping)
+	}
+	bad := api.Components.Responses["BadRequest"]
+	if !strings.Contains(bad.Description, "1000:参数验证失败") || bad.Content["application/json"].Schema.Ref != "#/components/schemas/"+openapi.ErrorResultName {
+		t.Fatalf("unexpected BadRequest response: %+v", bad)
+	}
+	if p := api.Components.Schemas[openapi.ErrorResultName]; p.Properties["code"].Type != openapi.OpenApiTypeInteger {
+		t.Fatalf("unexpected ErrorResult schema: %+v", p)
+	}
+}
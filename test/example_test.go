@@ -0,0 +1,32 @@
+package test
+
+import (
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+func TestOpenApiExample(t *testing.T) {
+	f := openapi.Field{Type: "int64", Comment: "编号 eg:1001"}
+	f.FillExample()
+	if f.Comment != "编号" || f.GetExample() != int64(1001) {
+		t.Fatalf("eg: marker not parsed: %+v", f)
+	}
+	f = openapi.Field{Type: "string", Array: true, Tag: "`json:\"tags\" example:\"a,b\"`", Comment: "标签 eg:c"}
+	f.FillExample()
+	list, ok := f.GetExample().([]any)
+	if !ok || len(list) != 2 || list[1] != "b" {
+		t.Fatalf("example tag not preferred: %+v", f.GetExample())
+	}
+	body := openapi.Property{Type: openapi.OpenApiTypeObject, Properties: map[string]openapi.Property{
+		"user": {Ref: "#/components/schemas/User"},
+	}}
+	c := openapi.Components{Schemas: map[string]openapi.Property{"User": {Type: openapi.OpenApiTypeObject, Properties: map[string]openapi.Property{
+		"name": {Type: openapi.OpenApiTypeString, Example: "cargen"},
+	}}}}
+	example, _ := c.Example(body).(map[string]any)
+	user, _ := example["user"].(map[string]any)
+	if user["name"] != "cargen" {
+		t.Fatalf("reference example not resolved: %+v", example)
+	}
+}
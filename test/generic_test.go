@@ -0,0 +1,55 @@
+package test
+
+import (
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+const genericTestFile = `package dto
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Page[T any] struct {
+	Total int64 ` + "`json:\"total\"`" + `
+	List  []T   ` + "`json:\"list\"`" + `
+}
+
+type Result[T any] struct {
+	Items Page[T] ` + "`json:\"items\"`" + `
+}
+
+type UserResult struct {
+	Data Result[User] ` + "`json:\"data\"`" + `
+}
+`
+
+func TestOpenApiGeneric(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":     "module demo\n\ngo 1.21\n",
+		"dto/dto.go": genericTestFile,
+	})
+	pkgs := openapi.Packages{}
+	pkgs.InitPackages(dir)
+	s := pkgs.FindStructPtr("demo/dto", "dto", "UserResult")
+	if s == nil {
+		t.Fatal("struct UserResult not found")
+	}
+	data := s.Fields[0].Struct
+	if data == nil || data.Name != "ResultOfUser" || data.Origin != "Result" {
+		t.Fatalf("unexpected instantiation %+v", data)
+	}
+	items := data.Fields[0].Struct
+	if items == nil || items.Name != "PageOfUser" {
+		t.Fatalf("unexpected nested instantiation %+v", items)
+	}
+	list := items.Fields[1]
+	if list.Type != "User" || !list.Array || list.Struct == nil || list.Struct.Name != "User" {
+		t.Errorf("type parameter not substituted %+v", list)
+	}
+	if name := openapi.GenericName("Map", []openapi.Field{{Type: "string"}, {Type: "User", Array: true}}); name != "MapOfStringAndUserList" {
+		t.Errorf("unexpected generic name %s", name)
+	}
+}
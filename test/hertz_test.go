@@ -0,0 +1,44 @@
+//go:build hertz
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+func TestHertzLoadRoute(t *testing.T) {
+	h := route.NewEngine(config.NewOptions(nil))
+	ok := func(ctx context.Context, rc *app.RequestContext) { rc.String(http.StatusOK, rc.GetString("group")) }
+	png := func(ctx context.Context, rc *app.RequestContext) {
+		if rc.Query("fail") != "" {
+			ctl.HertzBytes(rc, &ctl.Result{Code: 500, Msg: "fail"}, "image/png")
+			return
+		}
+		ctl.HertzBytes(rc, &ctl.Result{Data: []byte("png")}, "image/png")
+	}
+	ctl.HertzRegisterList{
+		{Method: http.MethodGet, Group: "/api/user/", Path: "order/list", Handles: []app.HandlerFunc{ok}},
+		{Method: http.MethodGet, Group: "/api/user/v2/", Path: "order/list", Handles: []app.HandlerFunc{ok}},
+		{Method: ctl.MethodAny, Group: "/api/user/", Path: "logo", Handles: []app.HandlerFunc{png}},
+	}.LoadRoute(h, ctl.HertzGroup{Path: "/api/user/v2", Handles: []app.HandlerFunc{func(ctx context.Context, rc *app.RequestContext) { rc.Set("group", "v2") }}})
+	for path, want := range map[string]string{"/api/user/order/list": "", "/api/user/v2/order/list": "v2"} {
+		w := ut.PerformRequest(h, http.MethodGet, path, nil)
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Fatalf("%s: %d %q", path, w.Code, w.Body.String())
+		}
+	}
+	for path, want := range map[string]string{"/api/user/logo": "image/png", "/api/user/logo?fail=1": "application/json; charset=utf-8"} {
+		w := ut.PerformRequest(h, http.MethodPost, path, nil)
+		if got := string(w.Result().Header.ContentType()); got != want {
+			t.Fatalf("%s content type %q", path, got)
+		}
+	}
+}
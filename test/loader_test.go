@@ -0,0 +1,36 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+func TestOpenApiLoadError(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":       "module demo\n\ngo 1.21\n",
+		"api/order.go": "package api\n\ntype Order struct {\n\tUser Missing `json:\"user\"`\n}\n",
+	})
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), "demo/api") || !strings.Contains(err.Error(), "Missing") {
+			t.Fatalf("package error not reported: %v", err)
+		}
+	}()
+	pkgs := openapi.Packages{}
+	pkgs.InitPackages(dir)
+}
+
+func TestOpenApiLoadModError(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{"api/order.go": errorTestController})
+	t.Setenv("GOFLAGS", "-mod=readonly") //go.mod未列出依赖 go list失败
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), "go.mod") {
+			t.Fatalf("go list error not reported: %v", err)
+		}
+	}()
+	pkgs := openapi.Packages{}
+	pkgs.InitPackages(dir)
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/mock"
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+func TestMockServer(t *testing.T) {
+	info := openapi.Property{Type: openapi.OpenApiTypeObject, Properties: map[string]openapi.Property{
+		"name":   {Type: openapi.OpenApiTypeString, Example: "cargen"},
+		"status": {Type: openapi.OpenApiTypeInteger, Enum: []any{1, 2}},
+	}}
+	result := openapi.Property{Type: openapi.OpenApiTypeObject, Properties: map[string]openapi.Property{
+		"code": {Type: openapi.OpenApiTypeInteger},
+		"msg":  {Type: openapi.OpenApiTypeString},
+		"data": {Ref: "#/components/schemas/Info"},
+	}}
+	api := openapi.OpenAPI{
+		Paths: openapi.ApiPathsMap{"/api/user/{id}": {"post": openapi.Method{
+			Parameters: []openapi.Parameter{{Name: "id", In: openapi.OpenApiInPath, Required: true, Schema: openapi.Property{Type: openapi.OpenApiTypeInteger}}},
+			RequestBody: openapi.RequestBody{Content: map[string]openapi.Content{"application/json": {Schema: openapi.Property{
+				Type: openapi.OpenApiTypeObject, Required: []string{"name"}, Properties: map[string]openapi.Property{"name": {Type: openapi.OpenApiTypeString}},
+			}}}},
+			Responses: map[string]openapi.Response{"200": {Content: map[string]openapi.Content{"application/json": {Schema: result}}}},
+		}}},
+		Components: openapi.Components{Schemas: map[string]openapi.Property{"Info": info}},
+	}
+	engine := mock.New(api, mock.Config{}).Engine()
+	request := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+	if w := request("/api/user/x", `{"name":"a"}`); w.Code != http.StatusBadRequest {
+		t.Fatal("path parameter type not validated", w.Body.String())
+	}
+	if w := request("/api/user/1", `{}`); w.Code != http.StatusBadRequest {
+		t.Fatal("required body field not validated", w.Body.String())
+	}
+	w := request("/api/user/1", `{"name":"a"}`)
+	var rsp struct {
+		Code int `json:"code"`
+		Data struct {
+			Name   string `json:"name"`
+			Status int    `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &rsp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || rsp.Code != 0 || rsp.Data.Name != "cargen" || (rsp.Data.Status != 1 && rsp.Data.Status != 2) {
+		t.Fatal("unexpected mock response", w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	engine = mock.New(api, mock.Config{ErrorRate: 1}).Engine()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/user/1", strings.NewReader(`{"name":"a"}`)))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatal("error injection not applied", w.Body.String())
+	}
+}
+
+func TestMockServerSecurity(t *testing.T) {
+	api := openapi.OpenAPI{
+		Paths: openapi.ApiPathsMap{
+			"/api/token": {"get": openapi.Method{Security: []map[string][]string{{"Token": {}}}}},
+			"/api/jwt":   {"get": openapi.Method{Security: []map[string][]string{{"JWT": {}}}}},
+		},
+		Components: openapi.Components{SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"Token": {Type: openapi.SecurityTypeApiKey, In: openapi.OpenApiInHeader, Name: "X-Token"},
+			"JWT":   {Type: openapi.SecurityTypeHttp, Scheme: "bearer"},
+		}},
+	}
+	engine := mock.New(api, mock.Config{}).Engine()
+	request := func(path, header string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set(header, "token")
+		engine.ServeHTTP(w, r)
+		return w.Code
+	}
+	if code := request("/api/token", openapi.DefaultTokenHeader); code != http.StatusUnauthorized {
+		t.Fatal("apiKey header scheme accepted Authorization", code)
+	}
+	if code := request("/api/token", "X-Token"); code != http.StatusOK {
+		t.Fatal("apiKey header scheme rejected its header", code)
+	}
+	if code := request("/api/jwt", openapi.DefaultTokenHeader); code != http.StatusOK {
+		t.Fatal("bearer scheme rejected Authorization", code)
+	}
+}
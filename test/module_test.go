@@ -0,0 +1,59 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// writeModule 在临时目录中按相对路径写入测试模块文件 返回模块根目录
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), os.ModePerm)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// writeCargenModule 写入引用本项目的测试模块 cargen通过replace指向当前源码
+// 测试模块只声明cargen 其余依赖通过-mod=mod从cargen的依赖中解析
+func writeCargenModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	t.Setenv("GOFLAGS", "-mod=mod")
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := map[string]string{
+		"go.mod": "module demo\n\ngo 1.22\n\nrequire github.com/carlos-yuan/cargen v0.0.0\n\nreplace github.com/carlos-yuan/cargen => " + filepath.ToSlash(root) + "\n",
+		"go.sum": string(sum),
+	}
+	for name, content := range files {
+		module[name] = content
+	}
+	return writeModule(t, module)
+}
+
+// goVet 在模块目录下执行go vet 检查生成的代码可以编译
+func goVet(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("go", append(append([]string{"vet"}, args...), "./...")...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go vet: %v\n%s", err, out)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"strings"
 	"testing"
 
 	openapi "github.com/carlos-yuan/cargen/open_api"
@@ -27,3 +28,157 @@ func TestOpenApi(t *testing.T) {
 	//	return nil, err
 	//}
 }
+
+func TestOpenApiAnnotate(t *testing.T) {
+	a := openapi.Api{Annotate: "GET|{id}|tag:order,admin|opid:getOrder|since:v2|deprecated|hidden|summary:订单详情"}
+	a.AnalysisAnnotate()
+	if a.HttpMethod != "GET" || a.RequestPath != "{id}" || len(a.Tags) != 2 || a.OperationId != "getOrder" || a.GetOperationId() != "getOrder" {
+		t.Fatalf("annotate not parsed: %+v", a)
+	}
+	if !a.Deprecated || !a.Hidden || a.Summary != "订单详情" || a.GetVersionPrefix() != "v2/" {
+		t.Fatalf("annotate flags not parsed: %+v", a)
+	}
+}
+
+func TestOpenApiMiddlewareAnnotate(t *testing.T) {
+	a := openapi.Api{Annotate: "POST|mw:audit, limit"}
+	a.AnalysisAnnotate()
+	if len(a.Middlewares) != 2 || a.Middlewares[1] != "limit" || a.RequestPath != "" {
+		t.Fatalf("middleware annotate not parsed: %+v", a)
+	}
+}
+
+func TestOpenApiStreamAnnotate(t *testing.T) {
+	a := openapi.Api{Annotate: "GET|stream:text/csv"}
+	a.AnalysisAnnotate()
+	if !a.IsStream() || a.ContentType != "text/csv" {
+		t.Fatalf("stream annotate not parsed: %+v", a)
+	}
+}
+
+func TestOpenApiPermission(t *testing.T) {
+	a := openapi.Api{Annotate: "GET|JWT|perm:order.read, order.write"}
+	a.AnalysisAnnotate()
+	if len(a.Permissions) != 2 || a.Permissions[1] != "order.write" || a.RequestPath != "" {
+		t.Fatalf("permission annotate not parsed: %+v", a)
+	}
+}
+
+func TestOpenApiMultipart(t *testing.T) {
+	a := openapi.Api{Params: &openapi.Struct{Fields: []openapi.Field{
+		{Name: "Dir", ParamName: "dir", Type: "string", In: openapi.TagParamFrom},
+		{Name: "Photos", ParamName: "photos", Type: "[]byte", Array: true, In: openapi.TagParamFile},
+	}}}
+	method := openapi.Method{}
+	a.FillRequestParams(&method)
+	content, ok := method.RequestBody.Content["multipart/form-data"]
+	if !ok || len(method.Parameters) != 0 {
+		t.Fatalf("multipart body not generated: %+v", method)
+	}
+	photos := content.Schema.Properties["photos"]
+	if photos.Type != openapi.OpenApiTypeArray || photos.Items == nil || photos.Items.Format != openapi.OpenApiFormatBinary {
+		t.Fatalf("file part not binary: %+v", photos)
+	}
+	if _, ok := content.Schema.Properties["dir"]; !ok {
+		t.Fatalf("form field not in multipart body: %+v", content.Schema)
+	}
+}
+
+func TestOpenApiRequired(t *testing.T) {
+	for validate, want := range map[string]bool{"required": true, "required,max=32": true, "omitempty,max=11": false, "max=11": false, "": false} {
+		f := openapi.Field{Name: "Phone", ParamName: "phone", Type: "string", In: openapi.TagParamFrom, Validate: validate}
+		if f.IsRequired() != want || f.ToParameter().Required != want {
+			t.Fatalf("validate %q required %v", validate, f.IsRequired())
+		}
+	}
+}
+
+func TestOpenApiEmbeddedParams(t *testing.T) {
+	a := openapi.Api{Params: &openapi.Struct{Fields: []openapi.Field{
+		{Type: "Paging", Struct: &openapi.Struct{Name: "Paging", Fields: []openapi.Field{ //嵌入 form字段展开为请求参数
+			{Name: "Page", ParamName: "page", Type: "int", In: openapi.TagParamFrom},
+			{Name: "Remark", ParamName: "remark", Type: "string", In: openapi.TagParamJson},
+		}}},
+		{Name: "Id", ParamName: "id", Type: "int64", In: openapi.OpenApiInPath, Validate: "required"},
+		{Name: "Name", ParamName: "name", Type: "string", In: openapi.TagParamJson, Validate: "required,max=32"},
+		{Name: "Phone", ParamName: "phone", Type: "string", In: openapi.TagParamJson, Validate: "omitempty,max=11"},
+	}}}
+	method := openapi.Method{}
+	a.FillRequestParams(&method)
+	params := make(map[string]openapi.Parameter)
+	for _, p := range method.Parameters {
+		params[p.Name] = p
+	}
+	if len(params) != 2 || params["page"].In != openapi.OpenApiInQuery || params["id"].In != openapi.OpenApiInPath || !params["id"].Required {
+		t.Fatalf("embedded form field not flattened: %+v", method.Parameters)
+	}
+	body := method.RequestBody.Content["application/json"].Schema
+	if _, ok := body.Properties["remark"]; !ok || len(body.Properties) != 3 {
+		t.Fatalf("embedded json field not kept in body: %+v", body.Properties)
+	}
+	if len(body.Required) != 1 || body.Required[0] != "name" {
+		t.Fatalf("unexpected required fields: %v", body.Required)
+	}
+}
+
+func TestOpenApiSecurityScheme(t *testing.T) {
+	a := openapi.Api{Annotate: "GET|Cookie:Admin"}
+	a.AnalysisAnnotate()
+	if s := a.GetSecurityScheme(); s.Type != openapi.SecurityTypeApiKey || s.In != openapi.OpenApiInCookie || s.Name != openapi.DefaultTokenCookie {
+		t.Fatalf("cookie scheme not generated: %+v", s)
+	}
+	a = openapi.Api{Annotate: "GET|OAuth2"}
+	a.AnalysisAnnotate()
+	if s := a.GetSecurityScheme(); s.Type != openapi.SecurityTypeOAuth2 || s.Flows["clientCredentials"].TokenUrl == "" || a.GetSecurityName() != openapi.AuthTypeOAuth2 {
+		t.Fatalf("oauth2 scheme not generated: %+v", s)
+	}
+	a = openapi.Api{Annotate: "GET|JWT:User"}
+	a.AnalysisAnnotate()
+	if s := a.GetSecurityScheme(); s.Type != openapi.SecurityTypeHttp || s.Scheme != "bearer" || a.GetSecurityName() != "User" {
+		t.Fatalf("jwt scheme not generated: %+v", s)
+	}
+}
+
+func TestOpenApiSecuritySchemeHeader(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{
+		"api/order.go":       errorTestController,
+		"config_origin.yaml": "web:\n  demo:\n    token:\n      JWT:\n        headerName: X-Token\n        type: JWT\n",
+	})
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+	api := pkgs.GetApi()
+	s := api.Components.SecuritySchemes[openapi.AuthTypeJWT]
+	if s == nil || s.Type != openapi.SecurityTypeApiKey || s.In != openapi.OpenApiInHeader || s.Name != "X-Token" {
+		t.Fatalf("jwt in custom header not documented as apiKey: %+v", s)
+	}
+}
+
+func TestOpenApiVersionAnnotate(t *testing.T) {
+	a := openapi.Api{Annotate: "GET|v2|list"}
+	a.AnalysisAnnotate()
+	if a.GetVersion() != "v2" || a.RequestPath != "list" {
+		t.Fatalf("version annotate not parsed: %+v", a)
+	}
+}
+
+func TestOpenApiVersionTags(t *testing.T) {
+	controller := func(pkg string) string {
+		return "package " + pkg + "\n\nimport ctl \"github.com/carlos-yuan/cargen/core/controller\"\n\n" +
+			"type User struct {\n\tctl.ControllerContext\n}\n\n" +
+			"// Detail 用户详情\n// @GET\nfunc (t *User) Detail(ctx ctl.ControllerContext) (string, error) {\n\treturn \"\", nil\n}\n"
+	}
+	dir := writeCargenModule(t, map[string]string{
+		"api/user/user.go":    controller("user"),
+		"api/user/v2/user.go": controller("v2"),
+	})
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+	api := pkgs.GetApi()
+	v1, v2 := api.Paths["/demo/user/detail"]["get"], api.Paths["/demo/v2/user/detail"]["get"]
+	if len(v1.Tags) != 1 || len(v2.Tags) != 1 || v1.Tags[0] == v2.Tags[0] || !strings.HasSuffix(v2.Tags[0], "-v2") {
+		t.Fatalf("package versions share a tag: %v %v", v1.Tags, v2.Tags)
+	}
+	if len(api.Tags) != 2 || len(api.Servers) != 0 {
+		t.Fatalf("unexpected tags or servers: %+v %+v", api.Tags, api.Servers)
+	}
+}
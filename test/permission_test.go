@@ -0,0 +1,47 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/cmd/gen"
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	e "github.com/carlos-yuan/cargen/core/error"
+)
+
+func TestCheckPermission(t *testing.T) {
+	checker := ctl.PermissionFunc(func(ctx ctl.ControllerContext, payload ctl.Payload, perms []string) (bool, error) {
+		return len(perms) == 1 && perms[0] == "order.read", nil
+	})
+	ctx := ctl.NewGinContext(nil)
+	ctl.CheckPermission(checker, ctx, "order.read")
+	defer func() {
+		err, ok := recover().(e.Err)
+		if !ok || err.Code != e.NoPermissionErrorCode {
+			t.Fatalf("permission denial not raised: %v", err)
+		}
+	}()
+	ctl.CheckPermission(checker, ctx, "order.write")
+}
+
+func TestPermissionManifestStale(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{
+		"api/order.go":               errorTestController,
+		"router/router.go":           "package router\n\nimport ctl \"github.com/carlos-yuan/cargen/core/controller\"\n\nvar (\n\trouterList ctl.GinRegisterList\n\ttokenMap   map[string]ctl.Token\n)\n",
+		"router/permission.gen.json": "{}\n",
+	})
+	file := filepath.Join(dir, gen.PermissionManifestFile)
+	gen.CreateWebRouter(dir, gen.WebGin)
+	if b, err := os.ReadFile(file); err != nil || !strings.Contains(string(b), `"order.read"`) {
+		t.Fatalf("permission manifest not written: %s %v", b, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api", "order.go"), []byte(strings.Replace(errorTestController, "|perm:order.read", "", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gen.CreateWebRouter(dir, gen.WebGin)
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Fatalf("stale permission manifest kept: %v", err)
+	}
+}
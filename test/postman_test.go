@@ -0,0 +1,115 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+// docTestFiles 文档转换测试控制器 订单更新带路径参数 请求体及JWT 列表带查询参数
+var docTestFiles = map[string]string{
+	"api/order.go": `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+// Order 订单
+type Order struct {
+	ctl.ControllerContext
+}
+
+type UpdateReq struct {
+	Id   int64  ` + "`uri:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + ` //名称
+}
+
+type ListReq struct {
+	Page int ` + "`form:\"page\"`" + ` //页码
+}
+
+// Update 更新订单
+// @POST|{id}|JWT
+func (t *Order) Update(ctx ctl.ControllerContext, req *UpdateReq) (bool, error) {
+	return true, nil
+}
+
+// List 订单列表
+// @GET
+func (t *Order) List(ctx ctl.ControllerContext, req *ListReq) ([]string, error) {
+	return nil, nil
+}
+`,
+	"api/goods.go": `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type Goods struct {
+	ctl.ControllerContext
+}
+
+// Ping 心跳
+// @GET
+func (t *Goods) Ping(ctx ctl.ControllerContext) (bool, error) {
+	return true, nil
+}
+`,
+}
+
+func TestPostman(t *testing.T) {
+	pkgs := openapi.Packages{}
+	pkgs.Init(writeCargenModule(t, docTestFiles))
+	c := pkgs.GetPostman()
+	if len(c.Item) != 2 || !strings.HasSuffix(c.Item[0].Name, "Goods") || !strings.HasSuffix(c.Item[1].Name, "Order") {
+		t.Fatalf("unexpected folders: %+v", c.Item)
+	}
+	order := c.Item[1]
+	if len(order.Item) != 2 || order.Item[0].Name != "更新订单" || order.Item[1].Name != "订单列表" {
+		t.Fatalf("unexpected requests: %+v", order.Item)
+	}
+	list := order.Item[1].Request
+	if list.Method != "GET" || list.Url.Raw != "{{baseUrl}}/demo/order/list?page=0" || list.Url.Query[0].Description != "页码" {
+		t.Fatalf("unexpected query request: %+v", list)
+	}
+	update := order.Item[0].Request
+	if update.Method != "POST" || update.Url.Raw != "{{baseUrl}}/demo/order/:id" || len(update.Url.Variable) != 1 || update.Url.Variable[0].Key != "id" {
+		t.Fatalf("unexpected path request: %+v", update.Url)
+	}
+	if update.Body == nil || update.Body.Mode != "raw" || !strings.Contains(update.Body.Raw, `"name"`) {
+		t.Fatalf("unexpected body: %+v", update.Body)
+	}
+	if update.Auth == nil || update.Auth.Bearer[0].Value != "{{jWTToken}}" || c.Variable[len(c.Variable)-1].Key != "jWTToken" {
+		t.Fatalf("unexpected auth: %+v %+v", update.Auth, c.Variable)
+	}
+}
+
+func TestPostmanAnyMethod(t *testing.T) {
+	pkgs := openapi.Packages{}
+	pkgs.Init(writeCargenModule(t, map[string]string{"api/hook.go": `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type Hook struct {
+	ctl.ControllerContext
+}
+
+// Notify 回调
+// @ANY
+func (t *Hook) Notify(ctx ctl.ControllerContext) (bool, error) {
+	return true, nil
+}
+`}))
+	c := pkgs.GetPostman()
+	if len(c.Item) != 1 {
+		t.Fatalf("unexpected folders: %+v", c.Item)
+	}
+	var methods []string
+	for _, item := range c.Item[0].Item {
+		if item.Request.Url.Raw != "{{baseUrl}}/demo/hook/notify" {
+			t.Fatalf("unexpected url: %s", item.Request.Url.Raw)
+		}
+		methods = append(methods, item.Request.Method)
+	}
+	if got := strings.Join(methods, ","); got != "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT,TRACE" {
+		t.Fatalf("ANY not expanded to concrete methods: %s", got)
+	}
+}
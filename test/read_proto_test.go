@@ -1,10 +1,16 @@
 package test
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/emicklei/proto"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
 )
 
 func TestReadProto(t *testing.T) {
@@ -26,3 +32,138 @@ func handleService(s *proto.Service) {
 func handleMessage(m *proto.Message) {
 	fmt.Println(m.Name)
 }
+
+func TestProtoOpenApi(t *testing.T) {
+	src := `syntax = "proto3";
+package shop;
+message GoodsReq {
+	int64 id = 1; // 编号
+	repeated string tags = 2;
+}
+message GoodsRsp {
+	string name = 1;
+}
+service Goods {
+	// Get 查询商品
+	rpc Get(GoodsReq) returns (GoodsRsp);
+}`
+	dir := writeModule(t, map[string]string{"biz/shop/rpc/shop.proto": src})
+	api, err := openapi.GetProtoApi(openapi.FindProtoFiles(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, ok := api.Paths["/shop.Goods/Get"]["post"]
+	if !ok || method.Summary != "查询商品" || method.OperationId != "shop.Goods.Get" {
+		t.Fatalf("rpc not documented: %+v", api.Paths)
+	}
+	req := api.Components.Schemas["shop.GoodsReq"]
+	if req.Properties["id"].Description != "编号" || req.Properties["tags"].Items == nil {
+		t.Fatalf("message fields not converted: %+v", req)
+	}
+}
+
+func TestProtoOpenApiTypes(t *testing.T) {
+	src := `syntax = "proto3";
+package shop;
+import "google/protobuf/timestamp.proto";
+// Status 订单状态
+enum Status {
+	UNKNOWN = 0;
+	PAID = 1; // 已支付
+}
+message Order {
+	message Item {
+		string sku = 1;
+	}
+	enum Kind {
+		NORMAL = 0;
+		GIFT = 1;
+	}
+	repeated Item items = 1;
+	map<string, Item> extra = 2;
+	Kind kind = 3;
+	Status status = 4;
+	google.protobuf.Timestamp created = 5;
+	oneof pay {
+		string card = 6;
+		Item coupon = 7;
+	}
+	shop.Order.Item main = 8;
+}
+service Orders {
+	rpc Get(Order) returns (Order);
+}`
+	dir := writeModule(t, map[string]string{"biz/shop/rpc/shop.proto": src})
+	api, err := openapi.GetProtoApi(openapi.FindProtoFiles(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := api.Components.Schemas["shop.Order"]
+	if _, ok := api.Components.Schemas["shop.Order.Item"]; !ok {
+		t.Fatalf("nested message not collected: %+v", api.Components.Schemas)
+	}
+	itemRef := openapi.OpenApiSchemasPrefix + "shop.Order.Item"
+	if items := order.Properties["items"]; items.Type != openapi.OpenApiTypeArray || items.Items == nil || items.Items.Ref != itemRef {
+		t.Fatalf("nested message not resolved in scope: %+v", items)
+	}
+	if main := order.Properties["main"]; main.Ref != itemRef {
+		t.Fatalf("qualified type not resolved: %+v", main)
+	}
+	if extra := order.Properties["extra"]; extra.Type != openapi.OpenApiTypeObject || extra.AdditionalProperties == nil || extra.AdditionalProperties.Ref != itemRef {
+		t.Fatalf("map field not converted: %+v", extra)
+	}
+	if card, coupon := order.Properties["card"], order.Properties["coupon"]; card.Type != openapi.OpenApiTypeString || coupon.Ref != itemRef {
+		t.Fatalf("oneof fields not converted: %+v %+v", card, coupon)
+	}
+	kind := order.Properties["kind"]
+	if kind.Type != openapi.OpenApiTypeInteger || len(kind.Enum) != 2 || kind.Enum[1] != 1 || kind.EnumVarNames[1] != "GIFT" {
+		t.Fatalf("nested enum not expanded: %+v", kind)
+	}
+	status := order.Properties["status"]
+	if len(status.EnumVarNames) != 2 || status.EnumVarNames[1] != "PAID" || status.EnumDescriptions[1] != "已支付" || status.Description != "Status 订单状态" {
+		t.Fatalf("package enum not expanded: %+v", status)
+	}
+	if created := order.Properties["created"]; created.Type != openapi.OpenApiTypeString || created.Format != "date-time" {
+		t.Fatalf("well-known type not mapped: %+v", created)
+	}
+}
+
+func TestProtoOpenApiMerge(t *testing.T) {
+	files := map[string]string{"biz/shop/rpc/shop.proto": `syntax = "proto3";
+package shop;
+message PingReq {}
+message PingRsp {}
+service Health {
+	rpc Ping(PingReq) returns (PingRsp);
+}`}
+	for name, content := range docTestFiles {
+		files[name] = content
+	}
+	dir := writeCargenModule(t, files)
+	out := filepath.Join(dir, "api.json")
+	openapi.GenFromProto("demo", "", "1.0", dir, out, true)
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var api openapi.OpenAPI
+	if err = json.Unmarshal(b, &api); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := api.Paths["/shop.Health/Ping"]["post"]; !ok {
+		t.Fatalf("rpc not merged: %+v", api.Paths)
+	}
+	if _, ok := api.Paths["/demo/order/list"]["get"]; !ok {
+		t.Fatalf("gin api lost in merge: %+v", api.Paths)
+	}
+	if _, ok := api.Components.Schemas["shop.PingReq"]; !ok {
+		t.Fatalf("rpc schemas not merged: %+v", api.Components.Schemas)
+	}
+	tags := make([]string, 0, len(api.Tags))
+	for _, tag := range api.Tags {
+		tags = append(tags, tag.Name)
+	}
+	if !sort.StringsAreSorted(tags) || !slices.Contains(tags, "Health") {
+		t.Fatalf("tags not merged: %v", tags)
+	}
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+func TestReference(t *testing.T) {
+	pkgs := openapi.Packages{}
+	pkgs.Init(writeCargenModule(t, docTestFiles))
+	ref := pkgs.GetReference()
+	ref.Title = "demo API"
+	if len(ref.Pages) != 2 || len(ref.Pages[1].Apis) != 2 {
+		t.Fatalf("unexpected pages: %+v", ref.Pages)
+	}
+	order := ref.Pages[1]
+	update := order.Apis[1]
+	if update.Method != "POST" || update.Path != "/demo/order/{id}" || update.Auth == "" || len(update.Params) != 2 {
+		t.Fatalf("unexpected api: %+v", update)
+	}
+	md := ref.Markdown()
+	if index := md["index.md"]; !strings.HasPrefix(index, "# demo API\n") || !strings.Contains(index, "]("+order.File+".md)") {
+		t.Fatalf("unexpected index:\n%s", index)
+	}
+	page := md[order.File+".md"]
+	for _, want := range []string{"## 更新订单\n\n`POST /demo/order/{id}`", "Auth: `JWT", "### Parameters", "| name | body | string |  |  | 名称 |", "## 订单列表", "### Response"} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("markdown page missing %q:\n%s", want, page)
+		}
+	}
+	html := ref.Html()
+	if !strings.Contains(html["index.html"], `<a href="`+order.File+`.html">`) || !strings.Contains(html[order.File+".html"], "<h2>更新订单</h2>") ||
+		!strings.Contains(html[order.File+".html"], "<td>page</td><td>query</td>") {
+		t.Fatalf("unexpected html:\n%s", html[order.File+".html"])
+	}
+}
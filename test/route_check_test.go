@@ -0,0 +1,99 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlos-yuan/cargen/cmd/gen"
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+func TestCheckRoutes(t *testing.T) {
+	ok := []gen.RouteEntry{
+		{Method: "GET", Path: "/api/user/order/:id", File: "order.go", Func: "Order.Detail"},
+		{Method: "GET", Path: "/api/user/order/list", File: "order.go", Func: "Order.List"},
+		{Method: "POST", Path: "/api/user/order/:id", File: "order.go", Func: "Order.Update"},
+	}
+	if err := gen.CheckRoutes(ok); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range [][]gen.RouteEntry{
+		{ok[0], {Method: "GET", Path: "/api/user/order/:id", File: "goods.go", Func: "Goods.Detail"}},
+		{ok[0], {Method: "GET", Path: "/api/user/order/:name", File: "goods.go", Func: "Goods.Detail"}},
+		{ok[1], {Method: "GET", Path: "/api/user/order/*path", File: "file.go", Func: "File.Static"}},
+		{ok[0], {Method: "ANY", Path: "/api/user/order/:id", File: "goods.go", Func: "Goods.Any"}},
+		{{Method: "FETCH", Path: "/api/user/order", File: "goods.go", Func: "Goods.Fetch"}},
+	} {
+		err := gen.CheckRoutes(bad)
+		if err == nil || !strings.Contains(err.Error(), "goods.go Goods.") && !strings.Contains(err.Error(), "file.go File.") {
+			t.Fatalf("conflict not reported: %v %v", bad, err)
+		}
+	}
+}
+
+func TestCheckStdRoutes(t *testing.T) {
+	ok := []gen.RouteEntry{
+		{Method: "GET", Path: "/api/a/{x}/c", File: "a.go", Func: "A.C"},
+		{Method: "GET", Path: "/api/a/list", File: "a.go", Func: "A.List"},
+		{Method: "ANY", Path: "/api/a/list", File: "a.go", Func: "A.Any"},
+		{Method: "POST", Path: "/api/a/{x}/c", File: "a.go", Func: "A.Update"},
+	}
+	if err := gen.CheckStdRoutes(ok); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range [][]gen.RouteEntry{
+		{ok[0], {Method: "GET", Path: "/api/a/b/{y}", File: "b.go", Func: "B.Y"}},
+		{ok[0], {Method: "GET", Path: "/api/a/{y}/c", File: "b.go", Func: "B.Y"}},
+		{ok[2], {Method: "ANY", Path: "/api/a/list", File: "b.go", Func: "B.Any"}},
+		{{Method: "FETCH", Path: "/api/a", File: "b.go", Func: "B.Fetch"}},
+	} {
+		err := gen.CheckStdRoutes(bad)
+		if err == nil || !strings.Contains(err.Error(), "b.go B.") || strings.Contains(err.Error(), "registered at") {
+			t.Fatalf("conflict not reported: %v %v", bad, err)
+		}
+		if len(bad) > 1 && !strings.Contains(err.Error(), "conflicts with "+bad[0].String()) {
+			t.Fatalf("conflicting route not reported: %v", err)
+		}
+	}
+}
+
+const anyTestController = `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type Proxy struct {
+	ctl.ControllerContext
+}
+
+// Forward 转发
+// @ANY
+func (t *Proxy) Forward(ctx ctl.ControllerContext) (string, error) {
+	return "", nil
+}
+
+// Tunnel 隧道
+// @CONNECT
+func (t *Proxy) Tunnel(ctx ctl.ControllerContext) (string, error) {
+	return "", nil
+}
+`
+
+func TestOpenApiAnyMethod(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{"api/proxy.go": anyTestController})
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+	api := pkgs.GetApi()
+	forward := api.Paths["/demo/proxy/forward"]
+	if len(forward) != len(openapi.APIMethods)-1 {
+		t.Fatalf("ANY not expanded to every OpenAPI operation: %v", forward)
+	}
+	if _, ok := forward["connect"]; ok {
+		t.Fatal("connect operation written to path item")
+	}
+	if id := forward["get"].OperationId; id != "demo.api.Forward.GET" {
+		t.Fatalf("unexpected operationId %s", id)
+	}
+	if _, ok := api.Paths["/demo/proxy/tunnel"]; ok {
+		t.Fatal("CONNECT api written to paths")
+	}
+}
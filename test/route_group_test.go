@@ -0,0 +1,26 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoadRouteGroup(t *testing.T) {
+	ok := func(ctx *gin.Context) { ctx.String(http.StatusOK, ctx.GetString("group")) }
+	g := gin.New()
+	ctl.GinRegisterList{
+		{Method: http.MethodGet, Group: "/api/user/", Path: "order/list", Handles: []gin.HandlerFunc{ok}},
+		{Method: http.MethodGet, Group: "/api/user/v2/", Path: "order/list", Handles: []gin.HandlerFunc{ok}},
+	}.LoadRoute(g, ctl.GinGroup{Path: "/api/user/v2", Handles: []gin.HandlerFunc{func(ctx *gin.Context) { ctx.Set("group", "v2") }}})
+	for path, want := range map[string]string{"/api/user/order/list": "", "/api/user/v2/order/list": "v2"} {
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Fatalf("%s: %d %q", path, w.Code, w.Body.String())
+		}
+	}
+}
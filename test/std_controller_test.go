@@ -0,0 +1,63 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	"github.com/carlos-yuan/cargen/core/middleware/stdmid"
+)
+
+func TestStdController(t *testing.T) {
+	type orderReq struct {
+		Id   int64  `uri:"id"`
+		Name string `form:"name" validate:"required"`
+	}
+	ctx := ctl.NewStdContext(nil)
+	mux := http.NewServeMux()
+	ctl.StdRegisterList{{Method: http.MethodGet, Group: "/api/user/", Path: "order/{id}", Handles: []ctl.StdHandler{stdmid.Panic(),
+		ctl.StdHandle(func(w http.ResponseWriter, r *http.Request) {
+			c := ctx.SetContext(ctl.NewStdRequest(w, r))
+			ctl.StdJSON(w, ctl.Handle(c, func(ctx ctl.ControllerContext, req *orderReq) (string, error) {
+				return fmt.Sprint(req.Id, req.Name), nil
+			}))
+		})}}}.LoadRoute(mux)
+	for path, want := range map[string]int{"/api/user/order/7?name=a": http.StatusOK, "/api/user/order/7": http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want || want == http.StatusOK && !strings.Contains(w.Body.String(), `"data":"7a"`) {
+			t.Fatalf("%s: %d %s", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestStdBindCache(t *testing.T) {
+	bind := func(r *http.Request, params any) {
+		ctl.NewStdContext(nil).SetContext(ctl.NewStdRequest(httptest.NewRecorder(), r)).Bind(params)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/order?name=query", strings.NewReader(`{"name":"body"}`))
+	r.Header.Set("Content-Type", "application/json")
+	{
+		type req struct {
+			Name string `form:"name"`
+		}
+		var q req
+		bind(r, &q)
+		if q.Name != "query" {
+			t.Fatalf("form not bound: %+v", q)
+		}
+	}
+	{
+		type req struct { //同名类型 不能复用上面的绑定方式
+			Name string `json:"name"`
+		}
+		var q req
+		bind(r, &q)
+		if q.Name != "body" {
+			t.Fatalf("json not bound: %+v", q)
+		}
+	}
+}
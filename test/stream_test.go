@@ -0,0 +1,86 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSSEResponse(t *testing.T) {
+	g := gin.New()
+	g.GET("/sse", func(ctx *gin.Context) {
+		ctl.GinSSE(ctx, &ctl.Result{Data: func(yield func(any) bool) {
+			_ = yield(ctl.Event{Event: "progress", Data: map[string]int{"done": 1}}) && yield("a\nb")
+		}})
+	})
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sse", nil))
+	if want := "event: progress\ndata: {\"done\":1}\n\ndata: a\ndata: b\n\n"; w.Body.String() != want || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("sse body %q", w.Body.String())
+	}
+}
+
+func TestStreamResponse(t *testing.T) {
+	g := gin.New()
+	g.GET("/file", func(ctx *gin.Context) {
+		ctl.GinStream(ctx, &ctl.Result{Data: &ctl.Stream{Reader: strings.NewReader("0123456789"), Name: "export.csv"}}, "text/csv")
+	})
+	g.GET("/png", func(ctx *gin.Context) {
+		if ctx.Query("fail") != "" {
+			ctl.GinBytes(ctx, &ctl.Result{Code: 500, Msg: "fail"}, "image/png")
+			return
+		}
+		ctl.GinBytes(ctx, &ctl.Result{Data: []byte("png")}, "image/png")
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/file", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	g.ServeHTTP(w, req)
+	if w.Code != http.StatusPartialContent || w.Body.String() != "234" || !strings.Contains(w.Header().Get("Content-Disposition"), "export.csv") ||
+		w.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("stream range %d %q %v", w.Code, w.Body.String(), w.Header())
+	}
+	for path, want := range map[string]string{"/png": "image/png", "/png?fail=1": "application/json; charset=utf-8"} {
+		w = httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Header().Get("Content-Type") != want {
+			t.Fatalf("%s content type %q %q", path, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+}
+
+// seq 与iter.Seq定义相同的具名迭代器
+type seq[V any] func(yield func(V) bool)
+
+func TestSSEEventSources(t *testing.T) {
+	events := make(chan ctl.Event, 2)
+	events <- ctl.Event{Event: "progress", Data: 1}
+	events <- ctl.Event{Data: "done"}
+	close(events)
+	values := make(chan any, 1)
+	values <- "a"
+	close(values)
+	sources := map[string]any{
+		"chan Event": events,
+		"chan any":   values,
+		"seq Event": seq[ctl.Event](func(yield func(ctl.Event) bool) {
+			_ = yield(ctl.Event{Event: "progress", Data: 1}) && yield(ctl.Event{Data: "done"})
+		}),
+		"seq any": seq[any](func(yield func(any) bool) { yield("a") }),
+	}
+	for name, data := range sources {
+		w := httptest.NewRecorder()
+		ctl.StdSSE(w, httptest.NewRequest(http.MethodGet, "/sse", nil), &ctl.Result{Data: data})
+		want := "event: progress\ndata: 1\n\ndata: done\n\n"
+		if strings.HasSuffix(name, "any") {
+			want = "data: a\n\n"
+		}
+		if w.Body.String() != want || w.Header().Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("%s: sse body %q", name, w.Body.String())
+		}
+	}
+}
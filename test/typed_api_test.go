@@ -0,0 +1,68 @@
+package test
+
+import (
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+const typedTestController = `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type Goods struct {
+	ctl.ControllerContext
+}
+
+type GoodsReq struct {
+	Name string ` + "`json:\"name\" binding:\"required\"`" + `
+}
+
+type GoodsInfo struct {
+	Id   int64  ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// Create 新增商品
+// @POST
+func (t *Goods) Create(ctx ctl.ControllerContext, req *GoodsReq) (*GoodsInfo, error) {
+	return nil, nil
+}
+
+// List 商品列表
+// @GET
+func (t *Goods) List(ctx ctl.ControllerContext) (*ctl.PageList[GoodsInfo], error) {
+	return nil, nil
+}
+`
+
+func TestOpenApiTypedApi(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{"api/goods.go": typedTestController})
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+	api := pkgs.GetApi()
+	create := api.Paths["/demo/goods/create"]["post"]
+	req := create.RequestBody.Content["application/json"].Schema
+	if req.Properties["name"].Type != openapi.OpenApiTypeString {
+		t.Fatalf("typed request body not documented: %+v", create.RequestBody)
+	}
+	if ref := create.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Goods.Create" {
+		t.Fatalf("typed response not documented: %s", ref)
+	}
+	data := api.Components.Schemas["Goods.Create"].Properties["data"]
+	if data.Properties["id"].Format != "int64" || data.Properties["name"].Type != openapi.OpenApiTypeString {
+		t.Fatalf("response data not converted: %+v", data)
+	}
+	list := api.Paths["/demo/goods/list"]["get"]
+	if len(list.Parameters) != 0 || len(list.RequestBody.Content) != 0 {
+		t.Fatalf("NoParams api documented with parameters: %+v", list)
+	}
+	data = api.Components.Schemas["Goods.List"].Properties["data"]
+	if data.Ref != "#/components/schemas/PageListOfGoodsInfo" {
+		t.Fatalf("generic response not referenced: %+v", data)
+	}
+	page := api.Components.Schemas["PageListOfGoodsInfo"]
+	if page.Properties["total"].Format != "int64" || page.Properties["list"].Items.Properties["name"].Type != openapi.OpenApiTypeString {
+		t.Fatalf("generic response not instantiated: %+v", page)
+	}
+}
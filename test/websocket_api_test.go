@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+const wsTestController = `package api
+
+import ctl "github.com/carlos-yuan/cargen/core/controller"
+
+type Room struct {
+	ctl.ControllerContext
+}
+
+type RoomReq struct {
+	Name string ` + "`form:\"name\"`" + `
+}
+
+// Join 加入房间
+// @ws|JWT
+func (t *Room) Join(ctx ctl.ControllerContext, conn ctl.WsConn, req *RoomReq) error {
+	return nil
+}
+
+// Echo 回声
+// @ws
+func (t *Room) Echo(ctx ctl.ControllerContext, conn ctl.WsConn) error {
+	return nil
+}
+`
+
+func TestOpenApiWebSocket(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{"api/room.go": wsTestController})
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+	api := pkgs.GetApi()
+	join, ok := api.Paths["/demo/room/join"]["get"]
+	if !ok || join.WebSocket == nil || join.WebSocket.Framing != openapi.ResponseTypeJSON || join.Responses["101"].Description == "" {
+		t.Fatalf("websocket api not documented: %+v", api.Paths)
+	}
+	if len(join.Parameters) != 1 || join.Parameters[0].Name != "name" || join.Parameters[0].In != openapi.OpenApiInQuery || len(join.Security) != 1 {
+		t.Fatalf("handshake parameters not documented: %+v", join)
+	}
+	b, err := json.Marshal(join)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"x-websocket":{"framing":"json"}`) {
+		t.Fatalf("x-websocket extension not written: %s", b)
+	}
+	echo := api.Paths["/demo/room/echo"]["get"]
+	if echo.WebSocket == nil || len(echo.Parameters) != 0 {
+		t.Fatalf("websocket api without handshake parameters: %+v", echo)
+	}
+}
+
+func TestOpenApiWebSocketSignature(t *testing.T) {
+	dir := writeCargenModule(t, map[string]string{"api/room.go": strings.Replace(wsTestController, "Echo(ctx ctl.ControllerContext, conn ctl.WsConn)", "Echo(ctx ctl.ControllerContext, conn string)", 1)})
+	defer func() {
+		if rec := recover(); rec == nil || !strings.Contains(rec.(string), "websocket api Echo") {
+			t.Fatalf("bad websocket signature not reported: %v", rec)
+		}
+	}()
+	pkgs := openapi.Packages{}
+	pkgs.Init(dir)
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ctl "github.com/carlos-yuan/cargen/core/controller"
+	e "github.com/carlos-yuan/cargen/core/error"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocket(t *testing.T) {
+	type roomReq struct {
+		Room string `form:"room"`
+	}
+	g := gin.New()
+	g.GET("/ws", func(ctx *gin.Context) {
+		c := ctl.NewGinContext(nil).SetContext(ctx)
+		ctl.GinWebSocketBind(ctx, c, func(ctx ctl.ControllerContext, conn ctl.WsConn, req *roomReq) error {
+			var msg map[string]string
+			if err := conn.ReadJSON(&msg); err != nil {
+				return err
+			}
+			msg["room"] = req.Room
+			if err := conn.WriteJSON(msg); err != nil {
+				return err
+			}
+			return e.ParamsValidatorError
+		})
+	})
+	srv := httptest.NewServer(g)
+	defer srv.Close()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws?room=a", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	var msg map[string]any
+	if err = websocket.JSON.Send(ws, map[string]string{"text": "hi"}); err == nil {
+		err = websocket.JSON.Receive(ws, &msg)
+	}
+	if err != nil || msg["room"] != "a" || msg["text"] != "hi" {
+		t.Fatalf("echo %v %v", msg, err)
+	}
+	if err = websocket.JSON.Receive(ws, &msg); err != nil || msg["msg"] != e.ParamsValidatorError.Msg {
+		t.Fatalf("error frame %v %v", msg, err)
+	}
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+
+	openapi "github.com/carlos-yuan/cargen/open_api"
+)
+
+// workspaceTestFiles go.work工作区 模块a引用同级模块b中的结构体
+var workspaceTestFiles = map[string]string{
+	"go.work":      "go 1.21\n\nuse (\n\t./a\n\t./b\n)\n",
+	"a/go.mod":     "module example.com/a\n\ngo 1.21\n",
+	"a/order/o.go": "package order\n\nimport user \"example.com/b/info\"\n\ntype Order struct {\n\tUser user.User `json:\"user\"`\n}\n",
+	"b/go.mod":     "module example.com/b\n\ngo 1.21\n",
+	"b/info/u.go":  "package info\n\ntype User struct {\n\tName string `json:\"name\"`\n}\n",
+}
+
+func TestOpenApiWorkspace(t *testing.T) {
+	dir := writeModule(t, workspaceTestFiles)
+	pkgs := openapi.Packages{}
+	pkgs.InitPackages(dir)
+	sct := pkgs.FindStructPtr("example.com/a/order", "order", "Order")
+	if sct == nil {
+		t.Fatal("struct Order not found")
+	}
+	f := sct.Fields[0]
+	if f.PkgPath != "example.com/b/info" || f.Pkg != "info" || f.Struct == nil || f.Struct.Name != "User" {
+		t.Fatalf("field User not resolved from workspace module: %+v", f)
+	}
+}
+
+func TestOpenApiWorkspaceModuleError(t *testing.T) {
+	files := map[string]string{
+		"go.work":      "go 1.21\n\nuse (\n\t./a\n\t./b\n\t./c\n)\n",
+		"c/go.mod":     "module example.com/c\n\ngo 1.21\n",
+		"c/api/bad.go": "package api\n\ntype Bad struct {\n\tUser Missing `json:\"user\"`\n}\n",
+	}
+	for name, content := range workspaceTestFiles {
+		if name != "go.work" {
+			files[name] = content
+		}
+	}
+	dir := writeModule(t, files)
+	pkgs := openapi.Packages{}
+	pkgs.InitPackages(dir)
+	if pkgs.FindStructPtr("example.com/a/order", "order", "Order") == nil {
+		t.Fatal("module a not loaded when module c fails")
+	}
+	for _, p := range pkgs {
+		if p.Path == "example.com/c/api" {
+			t.Fatal("failed module c should be skipped")
+		}
+	}
+}